@@ -21,23 +21,37 @@ func main() {
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
-	// Initialize Neo4j repository
+	// Initialize repository for the configured storage backend
 	repo, err := database.NewRepository(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to Neo4j: %v", err)
+		log.Fatalf("Failed to initialize %s storage: %v", cfg.StorageBackend, err)
 	}
 	defer repo.Close()
 
-	log.Printf("Connected to Neo4j at %s", cfg.Neo4jURI)
+	switch cfg.StorageBackend {
+	case config.StorageNeo4j:
+		log.Printf("Connected to Neo4j at %s", cfg.Neo4jURI)
+	case config.StorageMemory:
+		log.Printf("Using in-memory storage seeded from %q and %q", cfg.SeedPersonsCSV, cfg.SeedRelationshipsCSV)
+	case config.StorageSQLite:
+		log.Printf("Using SQLite database at %s", cfg.SQLitePath)
+	}
 
 	// Initialize handlers
 	treeHandler := handlers.NewTreeHandler(repo)
 	queryHandler := handlers.NewQueryHandler(repo)
-	uploadHandler := handlers.NewUploadHandler(cfg.AdminToken)
+	uploadHandler := handlers.NewUploadHandler(repo)
+	personHandler := handlers.NewPersonHandler(repo)
+	relationshipHandler := handlers.NewRelationshipHandler(repo)
+	genealogyHandler := handlers.NewGenealogyHandler(repo)
+	exportHandler := handlers.NewExportHandler(repo)
 
 	// Initialize rate limiter for query endpoint
 	rateLimiter := middleware.NewRateLimiter(cfg.RateLimitRequests, cfg.RateLimitWindowSeconds)
 
+	// Admin-only endpoints require the admin bearer token
+	adminAuth := middleware.AdminAuth(cfg.AdminToken)
+
 	// Setup router
 	router := gin.Default()
 
@@ -53,7 +67,8 @@ func main() {
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
-			"status": "healthy",
+			"status":  "healthy",
+			"storage": cfg.StorageBackend,
 		})
 	})
 
@@ -62,15 +77,46 @@ func main() {
 	{
 		// Tree endpoints (no rate limiting)
 		api.GET("/tree", treeHandler.GetTree)
+		api.GET("/tree.dot", treeHandler.GetTreeDOT)
+		api.GET("/tree.svg", treeHandler.GetTreeSVG)
 		api.GET("/persons", treeHandler.GetAllPersons)
 		api.GET("/person/:id", treeHandler.GetPerson)
 		api.GET("/person/:id/family", treeHandler.GetFamily)
 
+		// Genealogy endpoints
+		api.GET("/relationship", genealogyHandler.GetRelationship)
+		api.GET("/relatedness", genealogyHandler.GetRelatedness)
+		api.GET("/path", genealogyHandler.GetPath)
+		api.GET("/common-ancestors", genealogyHandler.GetCommonAncestors)
+		api.GET("/person/:id/ancestors", genealogyHandler.GetAncestors)
+		api.GET("/person/:id/descendants", genealogyHandler.GetDescendants)
+		api.GET("/person/:id/extended-family", genealogyHandler.GetExtendedFamily)
+
+		// Export endpoints
+		api.GET("/export/gedcom", exportHandler.ExportGEDCOM)
+		api.GET("/export/persons.csv", exportHandler.ExportPersonsCSV)
+		api.GET("/export/relationships.csv", exportHandler.ExportRelationshipsCSV)
+		api.GET("/export/snapshot", adminAuth, exportHandler.ExportSnapshot)
+
+		// Person write endpoints (admin only)
+		api.POST("/persons", adminAuth, personHandler.CreatePerson)
+		api.PUT("/person/:id", adminAuth, personHandler.UpdatePerson)
+		api.PATCH("/person/:id", adminAuth, personHandler.PatchPerson)
+		api.DELETE("/person/:id", adminAuth, personHandler.DeletePerson)
+
+		// Relationship write endpoints (admin only)
+		api.POST("/relationships", adminAuth, relationshipHandler.CreateRelationship)
+		api.PUT("/relationships/:type/:source/:target", adminAuth, relationshipHandler.UpdateRelationship)
+		api.DELETE("/relationships/:type/:source/:target", adminAuth, relationshipHandler.DeleteRelationship)
+
 		// Query endpoint (with rate limiting)
 		api.POST("/query", rateLimiter.Middleware(), queryHandler.ExecuteQuery)
 
-		// Upload endpoint (admin only, no rate limiting)
-		api.POST("/upload", uploadHandler.UploadCSV)
+		// Upload endpoints (admin only, no rate limiting)
+		api.POST("/upload", adminAuth, uploadHandler.UploadCSV)
+		api.POST("/upload/relationships", adminAuth, uploadHandler.UploadRelationshipsCSV)
+		api.POST("/upload/gedcom", adminAuth, uploadHandler.UploadGEDCOM)
+		api.POST("/upload/snapshot", adminAuth, uploadHandler.UploadSnapshot)
 	}
 
 	// Graceful shutdown
@@ -6,17 +6,51 @@ import (
 	"strings"
 )
 
+// Storage backends selectable with STORAGE_BACKEND
+const (
+	StorageNeo4j  = "neo4j"
+	StorageMemory = "memory"
+	StorageSQLite = "sqlite"
+)
+
+// Default per-operation database timeouts, used when the
+// DB_*_TIMEOUT_SECONDS variables are not set
+const (
+	DefaultDBLookupTimeoutSeconds = 5
+	DefaultDBReadTimeoutSeconds   = 10
+	DefaultDBQueryTimeoutSeconds  = 10
+	DefaultDBWriteTimeoutSeconds  = 10
+	DefaultDBBulkTimeoutSeconds   = 60
+)
+
 // Config holds all configuration for the application
 type Config struct {
 	// Server settings
 	Port    string
 	GinMode string
 
+	// Storage backend: "neo4j" (default), "memory" or "sqlite"
+	StorageBackend string
+
+	// Database file used by the SQLite backend
+	SQLitePath string
+
+	// CSV files loaded into the in-memory backend at startup (empty to skip)
+	SeedPersonsCSV       string
+	SeedRelationshipsCSV string
+
 	// Neo4j connection
 	Neo4jURI      string
 	Neo4jUsername string
 	Neo4jPassword string
 
+	// Per-operation database timeouts (0 disables the limit)
+	DBLookupTimeoutSeconds int
+	DBReadTimeoutSeconds   int
+	DBQueryTimeoutSeconds  int
+	DBWriteTimeoutSeconds  int
+	DBBulkTimeoutSeconds   int
+
 	// Read-only Neo4j credentials (for public query endpoint)
 	Neo4jReadOnlyUser     string
 	Neo4jReadOnlyPassword string
@@ -38,10 +72,22 @@ func Load() *Config {
 		Port:    getEnv("PORT", "8080"),
 		GinMode: getEnv("GIN_MODE", "debug"),
 
+		StorageBackend: strings.ToLower(getEnv("STORAGE_BACKEND", StorageNeo4j)),
+		SQLitePath:     getEnv("SQLITE_PATH", "family_tree.db"),
+
+		SeedPersonsCSV:       getEnv("SEED_PERSONS_CSV", "../data/example_persons.csv"),
+		SeedRelationshipsCSV: getEnv("SEED_RELATIONSHIPS_CSV", "../data/example_relationships.csv"),
+
 		Neo4jURI:      getEnv("NEO4J_URI", "bolt://localhost:7687"),
 		Neo4jUsername: getEnv("NEO4J_USERNAME", "neo4j"),
 		Neo4jPassword: getEnv("NEO4J_PASSWORD", "familytree123"),
 
+		DBLookupTimeoutSeconds: getEnvInt("DB_LOOKUP_TIMEOUT_SECONDS", DefaultDBLookupTimeoutSeconds),
+		DBReadTimeoutSeconds:   getEnvInt("DB_READ_TIMEOUT_SECONDS", DefaultDBReadTimeoutSeconds),
+		DBQueryTimeoutSeconds:  getEnvInt("DB_QUERY_TIMEOUT_SECONDS", DefaultDBQueryTimeoutSeconds),
+		DBWriteTimeoutSeconds:  getEnvInt("DB_WRITE_TIMEOUT_SECONDS", DefaultDBWriteTimeoutSeconds),
+		DBBulkTimeoutSeconds:   getEnvInt("DB_BULK_TIMEOUT_SECONDS", DefaultDBBulkTimeoutSeconds),
+
 		Neo4jReadOnlyUser:     getEnv("NEO4J_READ_ONLY_USER", ""),
 		Neo4jReadOnlyPassword: getEnv("NEO4J_READ_ONLY_PASSWORD", ""),
 
@@ -0,0 +1,121 @@
+package csvdata_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestWritePersons(t *testing.T) {
+	fx := repotest.LoadFixtures(t)
+	persons := append(slices.Clone(fx.Persons), models.Person{
+		ID: "quoted-001", Name: `Anne "Nan" O'Neil`, Aka: []string{"Nan", "Annie"}, Gender: "Female",
+		BirthDate: "1931-01-02", BirthPlace: "Cork, Ireland", DeathPlace: "Boston, USA", Profession: "Nurse\nMidwife",
+	}, models.Person{
+		ID: "formula-001", Name: "=HYPERLINK(\"http://example.com\")", Aka: []string{"Smith, Jr.", `C:\\Users`, "-1"}, Gender: "Male",
+		BirthDate: "1950-01-01", Profession: "@home", BirthPlace: "+91 Jaipur",
+	})
+
+	var buf bytes.Buffer
+	if err := csvdata.WritePersons(&buf, persons); err != nil {
+		t.Fatalf("WritePersons: %v", err)
+	}
+	if header, _, _ := strings.Cut(buf.String(), "\n"); header != strings.Join(csvdata.PersonColumns, ",") {
+		t.Errorf("header = %q", header)
+	}
+	// Spreadsheets show formulas as text
+	for _, cell := range []string{`"'=HYPERLINK(""http://example.com"")"`, "'@home", "'+91 Jaipur"} {
+		if !strings.Contains(buf.String(), cell) {
+			t.Errorf("output does not contain %s", cell)
+		}
+	}
+
+	rows, err := csvdata.ReadPersons(&buf)
+	if err != nil {
+		t.Fatalf("ReadPersons: %v", err)
+	}
+	var got []models.Person
+	for _, row := range rows {
+		if row.Err != nil {
+			t.Fatalf("row %d: %v", row.Row, row.Err)
+		}
+		got = append(got, row.Person)
+	}
+	if a, b := mustJSON(t, got), mustJSON(t, persons); a != b {
+		t.Errorf("read back\n%s\nwant\n%s", a, b)
+	}
+}
+
+func TestWriteRelationships(t *testing.T) {
+	fx := repotest.LoadFixtures(t)
+	start, end, reason := "1981-01-01", "1984-12-31", models.EndReasonDivorce
+	links := append(slices.Clone(fx.Links),
+		models.Link{Relationship: models.RelationshipSpouse, Source: "uncle-003", Target: "aunt-002", StartDate: &start, EndDate: &end, EndReason: &reason})
+
+	var buf bytes.Buffer
+	if err := csvdata.WriteRelationships(&buf, links); err != nil {
+		t.Fatalf("WriteRelationships: %v", err)
+	}
+
+	rows, err := csvdata.ReadRelationships(&buf)
+	if err != nil {
+		t.Fatalf("ReadRelationships: %v", err)
+	}
+	var got []models.Link
+	for _, row := range rows {
+		if row.Err != nil {
+			t.Fatalf("row %d: %v", row.Row, row.Err)
+		}
+		got = append(got, row.Link)
+	}
+	if a, b := mustJSON(t, got), mustJSON(t, links); a != b {
+		t.Errorf("read back\n%s\nwant\n%s", a, b)
+	}
+}
+
+func TestParseAka(t *testing.T) {
+	tests := map[string][]string{
+		"":                   {},
+		"Johnny, JD,, Papa ": {"Johnny", "JD", "Papa"},
+		`Smith\, Jr., Jay`:   {"Smith, Jr.", "Jay"},
+		`C:\\Users, C:\Temp`: {`C:\Users`, `C:\Temp`},
+	}
+	for value, want := range tests {
+		if got := csvdata.ParseAka(value); !slices.Equal(got, want) {
+			t.Errorf("ParseAka(%q) = %q; want %q", value, got, want)
+		}
+	}
+}
+
+func TestReadRelationshipsFieldCount(t *testing.T) {
+	// Rows with a wrong number of fields are reported, not fatal
+	csv := "type,person1_id,person2_id,start_date\nSPOUSE,a,b\nSIBLING,a,c,,\nPARENT_CHILD,a,d,\n"
+	rows, err := csvdata.ReadRelationships(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadRelationships: %v", err)
+	}
+	var failed []int
+	for _, row := range rows {
+		if row.Err != nil {
+			failed = append(failed, row.Row)
+		}
+	}
+	if len(rows) != 3 || !slices.Equal(failed, []int{1, 2}) || rows[2].Link.Target != "d" {
+		t.Errorf("read %d rows with errors in %v; want 3 with errors in rows 1 and 2", len(rows), failed)
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
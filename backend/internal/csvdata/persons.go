@@ -0,0 +1,280 @@
+package csvdata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// PersonColumns are the columns of data/template_persons.csv, in order
+var PersonColumns = []string{
+	"id",
+	"name",
+	"aka",
+	"gender",
+	"is_alive",
+	"birth_date",
+	"death_date",
+	"current_location",
+	"profession",
+	"photo_url",
+	"birth_place",
+	"death_place",
+}
+
+// RequiredPersonColumns must be present in the header of a persons CSV
+var RequiredPersonColumns = []string{"id", "name", "gender", "birth_date"}
+
+// PersonRow is a parsed row of a persons CSV
+type PersonRow struct {
+	Row    int               // 1-based data row number (the header is not counted)
+	Record map[string]string // raw values keyed by column name
+	Person models.Person
+	Err    error // set when the row itself could not be read
+}
+
+// MissingColumnError is returned when a required column is absent from the header
+type MissingColumnError struct {
+	Column   string
+	Required []string
+	Found    []string
+}
+
+func (e *MissingColumnError) Error() string {
+	return "required column missing: " + e.Column
+}
+
+// ReadPersons parses a persons CSV in the format of data/template_persons.csv.
+// Only an unreadable header is fatal; rows that cannot be read are returned
+// with Err set so that every problem in the file can be reported.
+func ReadPersons(r io.Reader) ([]PersonRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV headers: %w", err)
+	}
+
+	columnIndex := indexColumns(headers)
+	for _, col := range RequiredPersonColumns {
+		if _, exists := columnIndex[col]; !exists {
+			return nil, &MissingColumnError{Column: col, Required: RequiredPersonColumns, Found: headers}
+		}
+	}
+
+	var rows []PersonRow
+	rowNum := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !isParseError(err) {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			rows = append(rows, PersonRow{Row: rowNum, Err: err})
+			rowNum++
+			continue
+		}
+
+		record := recordFromRow(headers, row)
+		rows = append(rows, PersonRow{
+			Row:    rowNum,
+			Record: record,
+			Person: RecordToPerson(record),
+			Err:    checkFieldCount(headers, row),
+		})
+		rowNum++
+	}
+
+	return rows, nil
+}
+
+// RecordToPerson converts a CSV record keyed by column name into a Person,
+// following the same rules as scripts/csv_import.py
+func RecordToPerson(record map[string]string) models.Person {
+	person := models.Person{
+		ID:              strings.TrimSpace(record["id"]),
+		Name:            strings.TrimSpace(record["name"]),
+		Aka:             ParseAka(record["aka"]),
+		Gender:          strings.TrimSpace(record["gender"]),
+		IsAlive:         ParseBool(record["is_alive"]),
+		BirthDate:       strings.TrimSpace(record["birth_date"]),
+		CurrentLocation: strings.TrimSpace(record["current_location"]),
+		Profession:      strings.TrimSpace(record["profession"]),
+		PhotoURL:        strings.TrimSpace(record["photo_url"]),
+		BirthPlace:      strings.TrimSpace(record["birth_place"]),
+		DeathPlace:      strings.TrimSpace(record["death_place"]),
+	}
+
+	if deathDate := strings.TrimSpace(record["death_date"]); deathDate != "" {
+		person.DeathDate = &deathDate
+	}
+
+	return person
+}
+
+// WritePersons writes persons as a CSV with PersonColumns, which
+// ReadPersons reads back into the same persons. Cells that a spreadsheet
+// would take for a formula are quoted (see escapeFormula).
+func WritePersons(w io.Writer, persons []models.Person) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(PersonColumns); err != nil {
+		return err
+	}
+	for _, person := range persons {
+		record := PersonToRecord(person)
+		row := make([]string, len(PersonColumns))
+		for i, col := range PersonColumns {
+			row[i] = escapeFormula(record[col])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// PersonToRecord converts a Person into a CSV record keyed by column name,
+// the reverse of RecordToPerson
+func PersonToRecord(person models.Person) map[string]string {
+	record := map[string]string{
+		"id":               person.ID,
+		"name":             person.Name,
+		"aka":              FormatAka(person.Aka),
+		"gender":           person.Gender,
+		"is_alive":         strings.ToUpper(strconv.FormatBool(person.IsAlive)),
+		"birth_date":       person.BirthDate,
+		"current_location": person.CurrentLocation,
+		"profession":       person.Profession,
+		"photo_url":        person.PhotoURL,
+		"birth_place":      person.BirthPlace,
+		"death_place":      person.DeathPlace,
+	}
+	if person.DeathDate != nil {
+		record["death_date"] = *person.DeathDate
+	}
+	return record
+}
+
+// ParseAka splits a comma-separated list of nicknames. A backslash before a
+// comma or another backslash makes it part of the nickname, as FormatAka
+// writes; any other backslash is kept as it is.
+func ParseAka(value string) []string {
+	aka := make([]string, 0)
+	var nickname strings.Builder
+	add := func() {
+		if trimmed := strings.TrimSpace(nickname.String()); trimmed != "" {
+			aka = append(aka, trimmed)
+		}
+		nickname.Reset()
+	}
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value) && (value[i+1] == ',' || value[i+1] == '\\'):
+			i++
+			nickname.WriteByte(value[i])
+		case value[i] == ',':
+			add()
+		default:
+			nickname.WriteByte(value[i])
+		}
+	}
+	add()
+	return aka
+}
+
+// FormatAka joins nicknames with commas, escaping commas and backslashes
+// within them so that ParseAka splits them the same way
+func FormatAka(aka []string) string {
+	escaped := make([]string, len(aka))
+	for i, nickname := range aka {
+		escaped[i] = akaEscaper.Replace(nickname)
+	}
+	return strings.Join(escaped, ", ")
+}
+
+var akaEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`)
+
+// ParseBool accepts true/yes/1 in any case as true, anything else as false
+func ParseBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "1":
+		return true
+	}
+	return false
+}
+
+// isParseError reports whether err is a malformed row that the CSV reader
+// can skip past, as opposed to a failure of the underlying reader
+func isParseError(err error) bool {
+	var parseErr *csv.ParseError
+	return errors.As(err, &parseErr)
+}
+
+// IsBool reports whether value is a boolean understood by ParseBool:
+// true/false, yes/no or 1/0 in any case
+func IsBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "false", "yes", "no", "1", "0":
+		return true
+	}
+	return false
+}
+
+// checkFieldCount reports a row whose number of fields differs from the header
+func checkFieldCount(headers, row []string) error {
+	if len(row) != len(headers) {
+		return fmt.Errorf("expected %d fields, found %d", len(headers), len(row))
+	}
+	return nil
+}
+
+// indexColumns maps each header name to its column position
+func indexColumns(headers []string) map[string]int {
+	columnIndex := make(map[string]int, len(headers))
+	for i, h := range headers {
+		columnIndex[strings.TrimSpace(h)] = i
+	}
+	return columnIndex
+}
+
+// recordFromRow keys the values of a row by their header names, removing
+// the quote escapeFormula puts before formulas
+func recordFromRow(headers, row []string) map[string]string {
+	record := make(map[string]string, len(headers))
+	for i, value := range row {
+		if i < len(headers) {
+			record[strings.TrimSpace(headers[i])] = unescapeFormula(value)
+		}
+	}
+	return record
+}
+
+// formulaStart holds the characters that make a spreadsheet evaluate a cell
+const formulaStart = "=+-@"
+
+// escapeFormula puts a quote before a value that a spreadsheet would run as
+// a formula, which makes the spreadsheet show it as text
+func escapeFormula(value string) string {
+	if value != "" && strings.IndexByte(formulaStart, value[0]) >= 0 {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeFormula removes the quote escapeFormula adds
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.IndexByte(formulaStart, value[1]) >= 0 {
+		return value[1:]
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+package csvdata_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+)
+
+func TestReadPersons(t *testing.T) {
+	csv := `id,name,aka,gender,is_alive,birth_date,death_date,current_location,profession,photo_url
+ me-001 ,Alex Smith,"Al, Lexi,",Male,yes,1985-09-25,,San Francisco USA,Software Developer,
+gp-001,Robert Smith,,Male,FALSE,1928-07-14,2010-08-20,Boston USA,Carpenter,
+`
+	rows, err := csvdata.ReadPersons(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadPersons: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("read %d rows; want 2", len(rows))
+	}
+
+	me, gp := rows[0].Person, rows[1].Person
+	if rows[0].Row != 1 || me.ID != "me-001" || me.Name != "Alex Smith" || !me.IsAlive || me.DeathDate != nil {
+		t.Errorf("row 1 = %d %+v", rows[0].Row, me)
+	}
+	if strings.Join(me.Aka, "|") != "Al|Lexi" {
+		t.Errorf("aka = %q; want [Al Lexi]", me.Aka)
+	}
+	if rows[1].Row != 2 || gp.IsAlive || gp.DeathDate == nil || *gp.DeathDate != "2010-08-20" || len(gp.Aka) != 0 {
+		t.Errorf("row 2 = %d %+v", rows[1].Row, gp)
+	}
+}
+
+func TestReadPersonsErrors(t *testing.T) {
+	_, err := csvdata.ReadPersons(strings.NewReader("id,name,gender\np-001,Pat Doe,Female\n"))
+	var missingErr *csvdata.MissingColumnError
+	if !errors.As(err, &missingErr) || missingErr.Column != "birth_date" {
+		t.Errorf("missing birth_date column: error = %v; want MissingColumnError", err)
+	}
+
+	// Rows that cannot be read are returned with Err set, after the rows before them
+	rows, err := csvdata.ReadPersons(strings.NewReader("id,name,gender,birth_date\np-001,Pat Doe\np-002,\"Sam,Male,1950-01-01\n"))
+	if err != nil {
+		t.Fatalf("ReadPersons: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Err == nil || rows[1].Row != 2 || rows[1].Err == nil {
+		t.Errorf("rows = %+v; want a short row 1 and an unterminated quote in row 2, both with Err set", rows)
+	}
+}
@@ -0,0 +1,136 @@
+package csvdata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// RelationshipColumns are the columns of data/template_relationships.csv, in order
+var RelationshipColumns = []string{"type", "person1_id", "person2_id", "start_date", "end_date", "end_reason"}
+
+// RequiredRelationshipColumns must be present in the header of a relationships CSV.
+// start_date, end_date and end_reason are optional.
+var RequiredRelationshipColumns = []string{"type", "person1_id", "person2_id"}
+
+// RelationshipRow is a parsed row of a relationships CSV
+type RelationshipRow struct {
+	Row    int               // 1-based data row number (the header is not counted)
+	Record map[string]string // raw values keyed by column name
+	Link   models.Link
+	Err    error // set when the row itself could not be read
+}
+
+// ReadRelationships parses a relationships CSV in the format of
+// data/template_relationships.csv. person1_id is the link source (the parent
+// for PARENT_CHILD) and person2_id the target.
+func ReadRelationships(r io.Reader) ([]RelationshipRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV headers: %w", err)
+	}
+
+	columnIndex := indexColumns(headers)
+	for _, col := range RequiredRelationshipColumns {
+		if _, exists := columnIndex[col]; !exists {
+			return nil, &MissingColumnError{Column: col, Required: RequiredRelationshipColumns, Found: headers}
+		}
+	}
+
+	var rows []RelationshipRow
+	rowNum := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !isParseError(err) {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			rows = append(rows, RelationshipRow{Row: rowNum, Err: err})
+			rowNum++
+			continue
+		}
+
+		record := recordFromRow(headers, row)
+		rows = append(rows, RelationshipRow{
+			Row:    rowNum,
+			Record: record,
+			Link:   RecordToLink(record),
+			Err:    checkFieldCount(headers, row),
+		})
+		rowNum++
+	}
+
+	return rows, nil
+}
+
+// RecordToLink converts a CSV record keyed by column name into a Link
+func RecordToLink(record map[string]string) models.Link {
+	link := models.Link{
+		Source:       strings.TrimSpace(record["person1_id"]),
+		Target:       strings.TrimSpace(record["person2_id"]),
+		Relationship: strings.ToUpper(strings.TrimSpace(record["type"])),
+	}
+
+	if startDate := strings.TrimSpace(record["start_date"]); startDate != "" {
+		link.StartDate = &startDate
+	}
+	if endDate := strings.TrimSpace(record["end_date"]); endDate != "" {
+		link.EndDate = &endDate
+	}
+	if endReason := strings.ToLower(strings.TrimSpace(record["end_reason"])); endReason != "" {
+		link.EndReason = &endReason
+	}
+
+	return link
+}
+
+// WriteRelationships writes links as a CSV with RelationshipColumns, which
+// ReadRelationships reads back into the same links. Cells that a spreadsheet
+// would take for a formula are quoted (see escapeFormula).
+func WriteRelationships(w io.Writer, links []models.Link) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(RelationshipColumns); err != nil {
+		return err
+	}
+	for _, link := range links {
+		record := LinkToRecord(link)
+		row := make([]string, len(RelationshipColumns))
+		for i, col := range RelationshipColumns {
+			row[i] = escapeFormula(record[col])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// LinkToRecord converts a Link into a CSV record keyed by column name, the
+// reverse of RecordToLink
+func LinkToRecord(link models.Link) map[string]string {
+	record := map[string]string{
+		"type":       link.Relationship,
+		"person1_id": link.Source,
+		"person2_id": link.Target,
+	}
+	for col, value := range map[string]*string{
+		"start_date": link.StartDate,
+		"end_date":   link.EndDate,
+		"end_reason": link.EndReason,
+	} {
+		if value != nil {
+			record[col] = *value
+		}
+	}
+	return record
+}
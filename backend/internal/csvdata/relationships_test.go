@@ -0,0 +1,39 @@
+package csvdata_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+)
+
+func TestReadRelationships(t *testing.T) {
+	csv := `type,person1_id,person2_id,start_date,end_date,end_reason
+parent_child, dad-001 ,me-001,,,
+SPOUSE,me-001,spouse-001,2012-05-20,2020-01-01,Divorce
+`
+	rows, err := csvdata.ReadRelationships(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadRelationships: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("read %d rows; want 2", len(rows))
+	}
+
+	parent, spouse := rows[0].Link, rows[1].Link
+	if parent.Relationship != "PARENT_CHILD" || parent.Source != "dad-001" || parent.Target != "me-001" ||
+		parent.StartDate != nil || parent.EndDate != nil || parent.EndReason != nil {
+		t.Errorf("row 1 = %+v", parent)
+	}
+	if rows[1].Row != 2 || spouse.StartDate == nil || *spouse.StartDate != "2012-05-20" || spouse.EndDate == nil || *spouse.EndDate != "2020-01-01" ||
+		spouse.EndReason == nil || *spouse.EndReason != "divorce" {
+		t.Errorf("row 2 = %d %+v", rows[1].Row, spouse)
+	}
+
+	_, err = csvdata.ReadRelationships(strings.NewReader("type,person1_id\nSPOUSE,me-001\n"))
+	var missingErr *csvdata.MissingColumnError
+	if !errors.As(err, &missingErr) || missingErr.Column != "person2_id" {
+		t.Errorf("missing person2_id column: error = %v; want MissingColumnError", err)
+	}
+}
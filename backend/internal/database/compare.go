@@ -0,0 +1,57 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// samePerson reports whether two persons have identical stored fields
+func samePerson(a, b models.Person) bool {
+	return a.ID == b.ID &&
+		a.Name == b.Name &&
+		slices.Equal(a.Aka, b.Aka) &&
+		a.Gender == b.Gender &&
+		a.IsAlive == b.IsAlive &&
+		a.BirthDate == b.BirthDate &&
+		sameStringPtr(a.DeathDate, b.DeathDate) &&
+		a.CurrentLocation == b.CurrentLocation &&
+		a.Profession == b.Profession &&
+		a.PhotoURL == b.PhotoURL &&
+		a.BirthPlace == b.BirthPlace &&
+		a.DeathPlace == b.DeathPlace
+}
+
+// sameStringPtr compares two optional strings by value
+func sameStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// LinkKey identifies a relationship by type and endpoints. SPOUSE and
+// SIBLING are undirected, so their endpoints are ordered.
+func LinkKey(l models.Link) string {
+	source, target := l.Source, l.Target
+	if l.Relationship != models.RelationshipParentChild && target < source {
+		source, target = target, source
+	}
+	return l.Relationship + ":" + source + ":" + target
+}
+
+// sameLinkDetails reports whether two links carry identical dates and end reasons
+func sameLinkDetails(a, b models.Link) bool {
+	return sameStringPtr(a.StartDate, b.StartDate) && sameStringPtr(a.EndDate, b.EndDate) &&
+		sameStringPtr(a.EndReason, b.EndReason)
+}
+
+// checkRelationshipType rejects unknown relationship types. The Neo4j
+// backend splices the type into queries, so it must always be checked.
+func checkRelationshipType(relType string) error {
+	if !slices.Contains(models.RelationshipTypes, relType) {
+		return fmt.Errorf("unknown relationship type %q: %w", relType, ErrInvalidInput)
+	}
+	return nil
+}
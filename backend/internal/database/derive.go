@@ -0,0 +1,173 @@
+package database
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// derivedKin lists the IDs of relatives derived from PARENT_CHILD and
+// SPOUSE links rather than stored as SIBLING links
+type derivedKin struct {
+	fullSiblings []string
+	halfSiblings []string
+	stepSiblings []string
+	stepParents  []string
+}
+
+// deriveKin derives the siblings and step-relations of id. links must hold
+// at least the PARENT_CHILD links to id, the SPOUSE links of its parents,
+// and the PARENT_CHILD links to every child of its parents and their
+// spouses; other links are ignored.
+//
+// Siblings sharing both parents are full siblings, as are those sharing
+// the only parent either of them has on record. Siblings sharing one of
+// two known parents are half siblings. Children of a step-parent sharing
+// no parent with id are step-siblings.
+func deriveKin(id string, links []models.Link) derivedKin {
+	parents := make(map[string][]string)
+	children := make(map[string][]string)
+	spouses := make(map[string][]string)
+	for _, link := range links {
+		switch link.Relationship {
+		case models.RelationshipParentChild:
+			parents[link.Target] = appendUnique(parents[link.Target], link.Source)
+			children[link.Source] = appendUnique(children[link.Source], link.Target)
+		case models.RelationshipSpouse:
+			spouses[link.Source] = appendUnique(spouses[link.Source], link.Target)
+			spouses[link.Target] = appendUnique(spouses[link.Target], link.Source)
+		}
+	}
+
+	var kin derivedKin
+	own := parents[id]
+	seen := map[string]bool{id: true}
+	for _, parent := range own {
+		for _, sibling := range children[parent] {
+			if seen[sibling] {
+				continue
+			}
+			seen[sibling] = true
+
+			theirs := parents[sibling]
+			shared := 0
+			for _, p := range theirs {
+				if slices.Contains(own, p) {
+					shared++
+				}
+			}
+			if shared == 1 && (len(own) >= 2 || len(theirs) >= 2) {
+				kin.halfSiblings = append(kin.halfSiblings, sibling)
+			} else {
+				kin.fullSiblings = append(kin.fullSiblings, sibling)
+			}
+		}
+	}
+
+	for _, parent := range own {
+		for _, spouse := range spouses[parent] {
+			if spouse == id || slices.Contains(own, spouse) || slices.Contains(kin.stepParents, spouse) {
+				continue
+			}
+			kin.stepParents = append(kin.stepParents, spouse)
+		}
+	}
+	for _, stepParent := range kin.stepParents {
+		for _, child := range children[stepParent] {
+			if !seen[child] {
+				seen[child] = true
+				kin.stepSiblings = append(kin.stepSiblings, child)
+			}
+		}
+	}
+	return kin
+}
+
+// appendUnique appends id to ids unless it is already there
+func appendUnique(ids []string, id string) []string {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
+
+// buildPartnerships turns the SPOUSE links of person into partnerships in
+// chronological order. partners maps the other end of each link to the
+// stored person; links to missing persons are skipped.
+//
+// A partnership without an end date or reason ends by death when either
+// partner has a death date, on the earliest of them. One whose end date is
+// a partner's death date has ended by death. The latest partnership that
+// has not ended is the current one.
+func buildPartnerships(person models.Person, links []models.Link, partners map[string]models.Person) []models.Partnership {
+	partnerships := make([]models.Partnership, 0, len(links))
+	for _, link := range links {
+		partner, ok := partners[otherEnd(link, person.ID)]
+		if !ok {
+			continue
+		}
+		p := models.Partnership{
+			Partner:   partner,
+			StartDate: cloneStringPtr(link.StartDate),
+			EndDate:   cloneStringPtr(link.EndDate),
+			EndReason: cloneStringPtr(link.EndReason),
+		}
+
+		var deaths []string
+		died := false
+		for _, q := range []models.Person{person, partner} {
+			// IsAlive is false for anyone stored without it, so only a
+			// death date shows that a partner has died
+			if q.DeathDate != nil {
+				deaths = append(deaths, *q.DeathDate)
+				died = true
+			}
+		}
+		switch {
+		case p.EndDate == nil && p.EndReason == nil && died:
+			reason := models.EndReasonDeath
+			p.EndReason = &reason
+			if len(deaths) > 0 {
+				first := slices.Min(deaths)
+				p.EndDate = &first
+			}
+		case p.EndDate != nil && p.EndReason == nil && slices.Contains(deaths, *p.EndDate):
+			reason := models.EndReasonDeath
+			p.EndReason = &reason
+		}
+		partnerships = append(partnerships, p)
+	}
+
+	// Order by start date, or the end date when the start is unknown, with
+	// undated partnerships last
+	date := func(p models.Partnership) string {
+		switch {
+		case p.StartDate != nil:
+			return *p.StartDate
+		case p.EndDate != nil:
+			return *p.EndDate
+		}
+		return ""
+	}
+	slices.SortStableFunc(partnerships, func(a, b models.Partnership) int {
+		da, db := date(a), date(b)
+		switch {
+		case da == db:
+			return cmp.Compare(a.Partner.ID, b.Partner.ID)
+		case da == "":
+			return 1
+		case db == "":
+			return -1
+		}
+		return cmp.Compare(da, db)
+	})
+
+	for i := len(partnerships) - 1; i >= 0; i-- {
+		if partnerships[i].EndDate == nil && partnerships[i].EndReason == nil {
+			partnerships[i].Current = true
+			break
+		}
+	}
+	return partnerships
+}
@@ -0,0 +1,48 @@
+package database
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by Repository implementations. Use
+// errors.Is to test for them.
+var (
+	// ErrNotFound is returned when the requested person or relationship does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists is returned when creating a record whose key is already taken
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrCycle is returned when a PARENT_CHILD relationship would make a
+	// person their own ancestor
+	ErrCycle = errors.New("would make a person their own ancestor")
+
+	// ErrNotSupported is returned by backends that cannot perform an operation,
+	// such as raw Cypher queries on the in-memory backend
+	ErrNotSupported = errors.New("not supported by this storage backend")
+
+	// ErrInvalidInput is returned when the backend rejects an argument, such
+	// as an unknown relationship type or a malformed Cypher query
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrUnavailable is returned when the database cannot be reached or is
+	// temporarily unable to serve requests
+	ErrUnavailable = errors.New("database unavailable")
+
+	// ErrTimeout is returned when an operation runs past its deadline
+	ErrTimeout = errors.New("database operation timed out")
+)
+
+// sentinels lists every error that callers are expected to test for
+var sentinels = []error{
+	ErrNotFound, ErrAlreadyExists, ErrCycle, ErrNotSupported,
+	ErrInvalidInput, ErrUnavailable, ErrTimeout,
+}
+
+// classified reports whether err already wraps one of the sentinel errors
+func classified(err error) bool {
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	return false
+}
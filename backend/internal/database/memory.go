@@ -0,0 +1,504 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// MemoryRepository implements Repository interface with an in-memory graph.
+// It is used for tests and for running the app without Neo4j. Operations
+// never block, so contexts and timeouts are not consulted.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	persons map[string]models.Person
+	order   []string      // person IDs in insertion order
+	links   []models.Link // PARENT_CHILD links point from parent to child
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		persons: make(map[string]models.Person),
+	}
+}
+
+// NewMemoryRepositoryFromCSV creates an in-memory repository seeded from a
+// persons CSV and a relationships CSV in the formats of data/template_*.csv.
+// An empty path skips that file.
+func NewMemoryRepositoryFromCSV(personsPath, relationshipsPath string) (*MemoryRepository, error) {
+	repo := NewMemoryRepository()
+
+	if personsPath != "" {
+		f, err := os.Open(personsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open persons CSV: %w", err)
+		}
+		defer f.Close()
+
+		rows, err := csvdata.ReadPersons(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", personsPath, err)
+		}
+
+		persons := make([]models.Person, 0, len(rows))
+		for _, row := range rows {
+			if row.Err != nil {
+				return nil, fmt.Errorf("failed to read %s row %d: %w", personsPath, row.Row, row.Err)
+			}
+			persons = append(persons, row.Person)
+		}
+		if _, err := repo.UpsertPersons(context.Background(), persons); err != nil {
+			return nil, err
+		}
+	}
+
+	if relationshipsPath != "" {
+		f, err := os.Open(relationshipsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open relationships CSV: %w", err)
+		}
+		defer f.Close()
+
+		rows, err := csvdata.ReadRelationships(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", relationshipsPath, err)
+		}
+
+		links := make([]models.Link, 0, len(rows))
+		for _, row := range rows {
+			if row.Err != nil {
+				return nil, fmt.Errorf("failed to read %s row %d: %w", relationshipsPath, row.Row, row.Err)
+			}
+			links = append(links, row.Link)
+		}
+		if _, err := repo.UpsertRelationships(context.Background(), links); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", relationshipsPath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+// Close is a no-op for the in-memory repository
+func (r *MemoryRepository) Close() error {
+	return nil
+}
+
+// GetTreeData returns nodes and links for the family tree visualization
+func (r *MemoryRepository) GetTreeData(ctx context.Context, centerNodeID string, depth int) (*models.TreeResponse, error) {
+	if depth <= 0 {
+		depth = 2
+	}
+	if depth > 3 {
+		depth = 3
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tree := &models.TreeResponse{
+		Nodes: make([]models.Person, 0),
+		Links: make([]models.Link, 0),
+	}
+
+	if _, ok := r.persons[centerNodeID]; !ok {
+		return tree, nil
+	}
+
+	// Breadth-first search over every relationship, in either direction
+	included := map[string]bool{centerNodeID: true}
+	frontier := []string{centerNodeID}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, id := range frontier {
+			for _, link := range r.links {
+				var neighbor string
+				switch id {
+				case link.Source:
+					neighbor = link.Target
+				case link.Target:
+					neighbor = link.Source
+				default:
+					continue
+				}
+				if !included[neighbor] {
+					included[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	for _, id := range r.order {
+		if included[id] {
+			tree.Nodes = append(tree.Nodes, clonePerson(r.persons[id]))
+		}
+	}
+	for _, link := range r.links {
+		if included[link.Source] && included[link.Target] {
+			tree.Links = append(tree.Links, cloneLink(link))
+		}
+	}
+
+	return tree, nil
+}
+
+// GetPersonByID returns a person by their ID
+func (r *MemoryRepository) GetPersonByID(ctx context.Context, id string) (*models.Person, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	person, ok := r.persons[id]
+	if !ok {
+		return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+	}
+
+	person = clonePerson(person)
+	return &person, nil
+}
+
+// GetImmediateFamily returns the immediate family of a person
+func (r *MemoryRepository) GetImmediateFamily(ctx context.Context, id string) (*models.ImmediateFamily, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	person, ok := r.persons[id]
+	if !ok {
+		return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+	}
+
+	family := &models.ImmediateFamily{
+		Person:   clonePerson(person),
+		Parents:  make([]models.Person, 0),
+		Children: make([]models.Person, 0),
+		Siblings: make([]models.Person, 0),
+	}
+
+	var marriages []models.Link
+	partners := make(map[string]models.Person)
+	for _, link := range r.links {
+		switch {
+		case link.Relationship == models.RelationshipParentChild && link.Target == id:
+			family.Parents = append(family.Parents, clonePerson(r.persons[link.Source]))
+		case link.Relationship == models.RelationshipParentChild && link.Source == id:
+			family.Children = append(family.Children, clonePerson(r.persons[link.Target]))
+		case link.Relationship == models.RelationshipSibling && (link.Source == id || link.Target == id):
+			family.Siblings = append(family.Siblings, clonePerson(r.persons[otherEnd(link, id)]))
+		case link.Relationship == models.RelationshipSpouse && (link.Source == id || link.Target == id):
+			marriages = append(marriages, link)
+			partners[otherEnd(link, id)] = clonePerson(r.persons[otherEnd(link, id)])
+		}
+	}
+	family.Partnerships = buildPartnerships(family.Person, marriages, partners)
+
+	kin := deriveKin(id, r.links)
+	family.FullSiblings = r.clonePersons(kin.fullSiblings)
+	family.HalfSiblings = r.clonePersons(kin.halfSiblings)
+	family.StepSiblings = r.clonePersons(kin.stepSiblings)
+	family.StepParents = r.clonePersons(kin.stepParents)
+
+	return family, nil
+}
+
+// ExecuteQuery is not supported: there is no Cypher engine in memory
+func (r *MemoryRepository) ExecuteQuery(ctx context.Context, query string) (*models.QueryResponse, error) {
+	return nil, fmt.Errorf("raw queries: %w", ErrNotSupported)
+}
+
+// GetAllPersons returns all persons in insertion order
+func (r *MemoryRepository) GetAllPersons(ctx context.Context) ([]models.Person, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	persons := make([]models.Person, 0, len(r.order))
+	for _, id := range r.order {
+		persons = append(persons, clonePerson(r.persons[id]))
+	}
+	return persons, nil
+}
+
+// GetAllRelationships returns all relationships in insertion order
+func (r *MemoryRepository) GetAllRelationships(ctx context.Context) ([]models.Link, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	links := make([]models.Link, 0, len(r.links))
+	for _, link := range r.links {
+		links = append(links, cloneLink(link))
+	}
+	return links, nil
+}
+
+// UpsertPersons creates or updates persons by ID
+func (r *MemoryRepository) UpsertPersons(ctx context.Context, persons []models.Person) ([]models.UpsertStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]models.UpsertStatus, len(persons))
+	for i, person := range persons {
+		stored, found := r.persons[person.ID]
+		switch {
+		case !found:
+			statuses[i] = models.UpsertCreated
+			r.order = append(r.order, person.ID)
+		case samePerson(stored, person):
+			statuses[i] = models.UpsertUnchanged
+			continue
+		default:
+			statuses[i] = models.UpsertUpdated
+		}
+		r.persons[person.ID] = clonePerson(person)
+	}
+
+	return statuses, nil
+}
+
+// ExistingPersonIDs reports which of the given IDs belong to stored persons
+func (r *MemoryRepository) ExistingPersonIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := r.persons[id]; ok {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// UpsertRelationships creates relationships or updates their dates.
+// PARENT_CHILD links that would make someone their own ancestor are skipped.
+func (r *MemoryRepository) UpsertRelationships(ctx context.Context, links []models.Link) ([]models.UpsertStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, link := range links {
+		if err := r.checkEndpoints(link); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]models.UpsertStatus, len(links))
+	for i, link := range links {
+		idx := r.findLink(link.Relationship, link.Source, link.Target)
+		switch {
+		case idx < 0 && link.Relationship == models.RelationshipParentChild && r.isAncestor(link.Target, link.Source):
+			statuses[i] = models.UpsertFailed
+		case idx < 0:
+			statuses[i] = models.UpsertCreated
+			r.links = append(r.links, cloneLink(link))
+		case sameLinkDetails(r.links[idx], link):
+			statuses[i] = models.UpsertUnchanged
+		default:
+			statuses[i] = models.UpsertUpdated
+			r.links[idx].StartDate = cloneStringPtr(link.StartDate)
+			r.links[idx].EndDate = cloneStringPtr(link.EndDate)
+			r.links[idx].EndReason = cloneStringPtr(link.EndReason)
+		}
+	}
+
+	return statuses, nil
+}
+
+// CreatePerson creates a new person
+func (r *MemoryRepository) CreatePerson(ctx context.Context, person models.Person) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.persons[person.ID]; found {
+		return fmt.Errorf("person with id %s: %w", person.ID, ErrAlreadyExists)
+	}
+
+	r.persons[person.ID] = clonePerson(person)
+	r.order = append(r.order, person.ID)
+	return nil
+}
+
+// UpdatePerson replaces all fields of an existing person
+func (r *MemoryRepository) UpdatePerson(ctx context.Context, person models.Person) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.persons[person.ID]; !found {
+		return fmt.Errorf("person with id %s: %w", person.ID, ErrNotFound)
+	}
+
+	r.persons[person.ID] = clonePerson(person)
+	return nil
+}
+
+// DeletePerson deletes a person together with their relationships
+func (r *MemoryRepository) DeletePerson(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.persons[id]; !found {
+		return fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+	}
+
+	delete(r.persons, id)
+	r.order = slices.DeleteFunc(r.order, func(other string) bool { return other == id })
+	r.links = slices.DeleteFunc(r.links, func(link models.Link) bool {
+		return link.Source == id || link.Target == id
+	})
+	return nil
+}
+
+// CreateRelationship creates a relationship between two existing persons
+func (r *MemoryRepository) CreateRelationship(ctx context.Context, link models.Link) error {
+	if err := checkRelationshipType(link.Relationship); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkEndpoints(link); err != nil {
+		return err
+	}
+	if r.findLink(link.Relationship, link.Source, link.Target) >= 0 {
+		return fmt.Errorf("%s relationship between %s and %s: %w", link.Relationship, link.Source, link.Target, ErrAlreadyExists)
+	}
+	if link.Relationship == models.RelationshipParentChild && r.isAncestor(link.Target, link.Source) {
+		return fmt.Errorf("%s as parent of %s: %w", link.Source, link.Target, ErrCycle)
+	}
+
+	r.links = append(r.links, cloneLink(link))
+	return nil
+}
+
+// UpdateRelationship replaces the dates of an existing relationship
+func (r *MemoryRepository) UpdateRelationship(ctx context.Context, link models.Link) error {
+	if err := checkRelationshipType(link.Relationship); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.findLink(link.Relationship, link.Source, link.Target)
+	if idx < 0 {
+		return fmt.Errorf("%s relationship between %s and %s: %w", link.Relationship, link.Source, link.Target, ErrNotFound)
+	}
+
+	r.links[idx].StartDate = cloneStringPtr(link.StartDate)
+	r.links[idx].EndDate = cloneStringPtr(link.EndDate)
+	r.links[idx].EndReason = cloneStringPtr(link.EndReason)
+	return nil
+}
+
+// DeleteRelationship deletes a relationship between two persons
+func (r *MemoryRepository) DeleteRelationship(ctx context.Context, relType, source, target string) error {
+	if err := checkRelationshipType(relType); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.findLink(relType, source, target)
+	if idx < 0 {
+		return fmt.Errorf("%s relationship between %s and %s: %w", relType, source, target, ErrNotFound)
+	}
+
+	r.links = slices.Delete(r.links, idx, idx+1)
+	return nil
+}
+
+// checkEndpoints returns ErrNotFound if either person of a link is missing.
+// The caller must hold the lock.
+func (r *MemoryRepository) checkEndpoints(link models.Link) error {
+	for _, id := range []string{link.Source, link.Target} {
+		if _, found := r.persons[id]; !found {
+			return fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+		}
+	}
+	return nil
+}
+
+// findLink returns the index of the matching relationship, or -1. Only
+// PARENT_CHILD is matched by direction. The caller must hold the lock.
+func (r *MemoryRepository) findLink(relType, source, target string) int {
+	key := LinkKey(models.Link{Relationship: relType, Source: source, Target: target})
+	return slices.IndexFunc(r.links, func(link models.Link) bool {
+		return LinkKey(link) == key
+	})
+}
+
+// isAncestor reports whether ancestorID can be reached from id by following
+// PARENT_CHILD links upwards. The caller must hold the lock.
+func (r *MemoryRepository) isAncestor(ancestorID, id string) bool {
+	visited := map[string]bool{id: true}
+	stack := []string{id}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, link := range r.links {
+			if link.Relationship != models.RelationshipParentChild || link.Target != current {
+				continue
+			}
+			if link.Source == ancestorID {
+				return true
+			}
+			if !visited[link.Source] {
+				visited[link.Source] = true
+				stack = append(stack, link.Source)
+			}
+		}
+	}
+	return false
+}
+
+// clonePersons returns copies of the persons with the given IDs. The
+// caller must hold the lock.
+func (r *MemoryRepository) clonePersons(ids []string) []models.Person {
+	persons := make([]models.Person, 0, len(ids))
+	for _, id := range ids {
+		persons = append(persons, clonePerson(r.persons[id]))
+	}
+	return persons
+}
+
+// otherEnd returns the endpoint of a link that is not id
+func otherEnd(link models.Link, id string) string {
+	if link.Source == id {
+		return link.Target
+	}
+	return link.Source
+}
+
+// clonePerson returns a copy that shares no memory with p
+func clonePerson(p models.Person) models.Person {
+	p.Aka = slices.Clone(p.Aka)
+	if p.Aka == nil {
+		p.Aka = []string{}
+	}
+	p.DeathDate = cloneStringPtr(p.DeathDate)
+	return p
+}
+
+// cloneLink returns a copy that shares no memory with l
+func cloneLink(l models.Link) models.Link {
+	l.StartDate = cloneStringPtr(l.StartDate)
+	l.EndDate = cloneStringPtr(l.EndDate)
+	l.EndReason = cloneStringPtr(l.EndReason)
+	return l
+}
+
+// cloneStringPtr returns a pointer to a copy of *s, or nil
+func cloneStringPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
@@ -0,0 +1,42 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) database.Repository {
+		return database.NewMemoryRepository()
+	})
+}
+
+func TestMemoryRepositoryFromCSV(t *testing.T) {
+	ctx := context.Background()
+	repo, err := database.NewMemoryRepositoryFromCSV("../../../data/example_persons.csv", "../../../data/example_relationships.csv")
+	if err != nil {
+		t.Fatalf("NewMemoryRepositoryFromCSV: %v", err)
+	}
+
+	persons, err := repo.GetAllPersons(ctx)
+	if err != nil || len(persons) != 35 || persons[0].ID == "" {
+		t.Errorf("GetAllPersons = %d persons, %v; want the 35 example persons", len(persons), err)
+	}
+
+	family, err := repo.GetImmediateFamily(ctx, "me-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	if len(family.Parents) != 2 || len(family.Children) != 2 || len(family.Siblings) != 2 ||
+		len(family.Partnerships) != 1 || family.Partnerships[0].Partner.ID != "spouse-001" {
+		t.Errorf("family of me-001 = %d parents, %d children, %d siblings, partnerships %+v",
+			len(family.Parents), len(family.Children), len(family.Siblings), family.Partnerships)
+	}
+
+	if _, err := database.NewMemoryRepositoryFromCSV("missing.csv", ""); err == nil {
+		t.Error("NewMemoryRepositoryFromCSV with a missing file: no error")
+	}
+}
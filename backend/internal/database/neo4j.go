@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/heemankverma/family_tree/backend/internal/config"
@@ -14,6 +16,7 @@ import (
 type Neo4jRepository struct {
 	driver   neo4j.DriverWithContext
 	database string
+	timeouts Timeouts
 }
 
 // NewNeo4jRepository creates a new Neo4j repository
@@ -23,7 +26,7 @@ func NewNeo4jRepository(cfg *config.Config) (*Neo4jRepository, error) {
 		neo4j.BasicAuth(cfg.Neo4jUsername, cfg.Neo4jPassword, ""),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Neo4j driver: %w", err)
+		return nil, fmt.Errorf("failed to create Neo4j driver: %w", classifyNeo4jError(err))
 	}
 
 	// Verify connectivity
@@ -31,12 +34,13 @@ func NewNeo4jRepository(cfg *config.Config) (*Neo4jRepository, error) {
 	defer cancel()
 
 	if err := driver.VerifyConnectivity(ctx); err != nil {
-		return nil, fmt.Errorf("failed to connect to Neo4j: %w", err)
+		return nil, fmt.Errorf("failed to connect to Neo4j: %w", classifyNeo4jError(err))
 	}
 
 	return &Neo4jRepository{
 		driver:   driver,
 		database: "neo4j",
+		timeouts: TimeoutsFromConfig(cfg),
 	}, nil
 }
 
@@ -48,7 +52,7 @@ func (r *Neo4jRepository) Close() error {
 }
 
 // GetTreeData returns nodes and links for the family tree visualization
-func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.TreeResponse, error) {
+func (r *Neo4jRepository) GetTreeData(ctx context.Context, centerNodeID string, depth int) (*models.TreeResponse, error) {
 	if depth <= 0 {
 		depth = 2
 	}
@@ -56,7 +60,7 @@ func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.T
 		depth = 3
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
 	defer cancel()
 
 	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
@@ -75,7 +79,7 @@ func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.T
 		UNWIND nodes AS n2
 		OPTIONAL MATCH (n1)-[rel:PARENT_CHILD|SPOUSE|SIBLING]->(n2)
 		WHERE rel IS NOT NULL
-		WITH nodes, collect(DISTINCT {source: n1.id, target: n2.id, type: type(rel), start_date: rel.start_date, end_date: rel.end_date}) AS rels
+		WITH nodes, collect(DISTINCT {source: n1.id, target: n2.id, type: type(rel), start_date: rel.start_date, end_date: rel.end_date, end_reason: rel.end_reason}) AS rels
 		RETURN nodes, rels
 	`
 
@@ -86,7 +90,7 @@ func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.T
 		"centerId": centerNodeID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
 	}
 
 	if result.Next(ctx) {
@@ -120,6 +124,7 @@ func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.T
 								Relationship: relType,
 								StartDate:    getStringPtrFromInterface(relMap["start_date"]),
 								EndDate:      getStringPtrFromInterface(relMap["end_date"]),
+								EndReason:    getStringPtrFromInterface(relMap["end_reason"]),
 							}
 							links = append(links, link)
 						}
@@ -130,7 +135,7 @@ func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.T
 	}
 
 	if err := result.Err(); err != nil {
-		return nil, fmt.Errorf("error processing results: %w", err)
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
 	}
 
 	return &models.TreeResponse{
@@ -140,8 +145,8 @@ func (r *Neo4jRepository) GetTreeData(centerNodeID string, depth int) (*models.T
 }
 
 // GetPersonByID returns a person by their ID
-func (r *Neo4jRepository) GetPersonByID(id string) (*models.Person, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *Neo4jRepository) GetPersonByID(ctx context.Context, id string) (*models.Person, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Lookup)
 	defer cancel()
 
 	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
@@ -151,7 +156,7 @@ func (r *Neo4jRepository) GetPersonByID(id string) (*models.Person, error) {
 
 	result, err := session.Run(ctx, query, map[string]interface{}{"id": id})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
 	}
 
 	if result.Next(ctx) {
@@ -165,15 +170,15 @@ func (r *Neo4jRepository) GetPersonByID(id string) (*models.Person, error) {
 	}
 
 	if err := result.Err(); err != nil {
-		return nil, fmt.Errorf("error processing results: %w", err)
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
 	}
 
-	return nil, fmt.Errorf("person with id %s not found", id)
+	return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
 }
 
 // GetImmediateFamily returns the immediate family of a person
-func (r *Neo4jRepository) GetImmediateFamily(id string) (*models.ImmediateFamily, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *Neo4jRepository) GetImmediateFamily(ctx context.Context, id string) (*models.ImmediateFamily, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Lookup)
 	defer cancel()
 
 	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
@@ -181,17 +186,21 @@ func (r *Neo4jRepository) GetImmediateFamily(id string) (*models.ImmediateFamily
 
 	query := `
 		MATCH (p:Person {id: $id})
-		OPTIONAL MATCH (p)-[:SPOUSE]-(spouse:Person)
+		OPTIONAL MATCH (p)-[marriage:SPOUSE]-(spouse:Person)
 		OPTIONAL MATCH (p)<-[:PARENT_CHILD]-(parent:Person)
 		OPTIONAL MATCH (p)-[:PARENT_CHILD]->(child:Person)
 		OPTIONAL MATCH (p)-[:SIBLING]-(sibling:Person)
-		RETURN p, spouse, collect(DISTINCT parent) AS parents,
+		RETURN p, collect(DISTINCT CASE WHEN spouse IS NULL THEN NULL ELSE {
+		           partner: spouse, start_date: marriage.start_date,
+		           end_date: marriage.end_date, end_reason: marriage.end_reason
+		       } END) AS marriages,
+		       collect(DISTINCT parent) AS parents,
 		       collect(DISTINCT child) AS children, collect(DISTINCT sibling) AS siblings
 	`
 
 	result, err := session.Run(ctx, query, map[string]interface{}{"id": id})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
 	}
 
 	if result.Next(ctx) {
@@ -210,13 +219,32 @@ func (r *Neo4jRepository) GetImmediateFamily(id string) (*models.ImmediateFamily
 			}
 		}
 
-		// Get spouse
-		if spouseVal, ok := record.Get("spouse"); ok && spouseVal != nil {
-			if node, ok := spouseVal.(neo4j.Node); ok {
-				spouse := nodeToModel(node)
-				family.Spouse = &spouse
+		// Get partnerships
+		var marriages []models.Link
+		partners := make(map[string]models.Person)
+		if marriagesVal, ok := record.Get("marriages"); ok {
+			if marriagesList, ok := marriagesVal.([]interface{}); ok {
+				for _, mVal := range marriagesList {
+					marriage, ok := mVal.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					if node, ok := marriage["partner"].(neo4j.Node); ok {
+						partner := nodeToModel(node)
+						partners[partner.ID] = partner
+						marriages = append(marriages, models.Link{
+							Source:       id,
+							Target:       partner.ID,
+							Relationship: models.RelationshipSpouse,
+							StartDate:    getStringPtrFromInterface(marriage["start_date"]),
+							EndDate:      getStringPtrFromInterface(marriage["end_date"]),
+							EndReason:    getStringPtrFromInterface(marriage["end_reason"]),
+						})
+					}
+				}
 			}
 		}
+		family.Partnerships = buildPartnerships(family.Person, marriages, partners)
 
 		// Get parents
 		if parentsVal, ok := record.Get("parents"); ok {
@@ -251,19 +279,102 @@ func (r *Neo4jRepository) GetImmediateFamily(id string) (*models.ImmediateFamily
 			}
 		}
 
+		links, persons, err := r.kinLinks(ctx, session, id)
+		if err != nil {
+			return nil, err
+		}
+		kin := deriveKin(id, links)
+		family.FullSiblings = pickPersons(persons, kin.fullSiblings)
+		family.HalfSiblings = pickPersons(persons, kin.halfSiblings)
+		family.StepSiblings = pickPersons(persons, kin.stepSiblings)
+		family.StepParents = pickPersons(persons, kin.stepParents)
+
 		return family, nil
 	}
 
 	if err := result.Err(); err != nil {
-		return nil, fmt.Errorf("error processing results: %w", err)
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+
+	return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+}
+
+// kinLinks returns the links deriveKin needs for a person, with the
+// persons they lead to: the SPOUSE links of the person's parents and the
+// PARENT_CHILD links of every child of the parents and their spouses
+func (r *Neo4jRepository) kinLinks(ctx context.Context, session neo4j.SessionWithContext, id string) ([]models.Link, map[string]models.Person, error) {
+	query := `
+		MATCH (p:Person {id: $id})<-[:PARENT_CHILD]-(parent:Person)
+		OPTIONAL MATCH (parent)-[:SPOUSE]-(spouse:Person)
+		WITH collect(DISTINCT parent) AS parents, collect(DISTINCT spouse) AS spouses,
+		     collect(DISTINCT CASE WHEN spouse IS NULL THEN NULL ELSE [parent.id, spouse.id] END) AS marriages
+		UNWIND parents + spouses AS elder
+		OPTIONAL MATCH (elder)-[:PARENT_CHILD]->(kid:Person)
+		OPTIONAL MATCH (kidParent:Person)-[:PARENT_CHILD]->(kid)
+		RETURN spouses, marriages, collect(DISTINCT kid) AS kids,
+		       collect(DISTINCT CASE WHEN kid IS NULL THEN NULL ELSE [kidParent.id, kid.id] END) AS parentage
+	`
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
 	}
 
-	return nil, fmt.Errorf("person with id %s not found", id)
+	var links []models.Link
+	persons := make(map[string]models.Person)
+	if result.Next(ctx) {
+		record := result.Record().AsMap()
+
+		for _, key := range []string{"spouses", "kids"} {
+			nodes, _ := record[key].([]interface{})
+			for _, v := range nodes {
+				if node, ok := v.(neo4j.Node); ok {
+					person := nodeToModel(node)
+					persons[person.ID] = person
+				}
+			}
+		}
+
+		for _, column := range []struct{ key, relType string }{
+			{"marriages", models.RelationshipSpouse},
+			{"parentage", models.RelationshipParentChild},
+		} {
+			pairs, _ := record[column.key].([]interface{})
+			for _, v := range pairs {
+				pair, _ := v.([]interface{})
+				if len(pair) != 2 {
+					continue
+				}
+				source, sourceOK := pair[0].(string)
+				target, targetOK := pair[1].(string)
+				if sourceOK && targetOK {
+					links = append(links, models.Link{Relationship: column.relType, Source: source, Target: target})
+				}
+			}
+		}
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+
+	return links, persons, nil
+}
+
+// pickPersons returns the persons with the given IDs in the same order
+func pickPersons(persons map[string]models.Person, ids []string) []models.Person {
+	picked := make([]models.Person, 0, len(ids))
+	for _, id := range ids {
+		if person, ok := persons[id]; ok {
+			picked = append(picked, person)
+		}
+	}
+	return picked
 }
 
 // ExecuteQuery executes a raw Cypher query (read-only)
-func (r *Neo4jRepository) ExecuteQuery(query string) (*models.QueryResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (r *Neo4jRepository) ExecuteQuery(ctx context.Context, query string) (*models.QueryResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Query)
 	defer cancel()
 
 	// Use read-only session
@@ -275,12 +386,12 @@ func (r *Neo4jRepository) ExecuteQuery(query string) (*models.QueryResponse, err
 
 	result, err := session.Run(ctx, query, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
 	}
 
 	records, err := result.Collect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect results: %w", err)
+		return nil, fmt.Errorf("failed to collect results: %w", classifyNeo4jError(err))
 	}
 
 	results := make([]interface{}, 0, len(records))
@@ -300,8 +411,8 @@ func (r *Neo4jRepository) ExecuteQuery(query string) (*models.QueryResponse, err
 }
 
 // GetAllPersons returns all persons in the database
-func (r *Neo4jRepository) GetAllPersons() ([]models.Person, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (r *Neo4jRepository) GetAllPersons(ctx context.Context) ([]models.Person, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
 	defer cancel()
 
 	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
@@ -311,7 +422,7 @@ func (r *Neo4jRepository) GetAllPersons() ([]models.Person, error) {
 
 	result, err := session.Run(ctx, query, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
 	}
 
 	var persons []models.Person
@@ -325,12 +436,645 @@ func (r *Neo4jRepository) GetAllPersons() ([]models.Person, error) {
 	}
 
 	if err := result.Err(); err != nil {
-		return nil, fmt.Errorf("error processing results: %w", err)
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
 	}
 
 	return persons, nil
 }
 
+// GetAllRelationships returns all relationships in the database
+func (r *Neo4jRepository) GetAllRelationships(ctx context.Context) ([]models.Link, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	query := `
+		MATCH (a:Person)-[rel:PARENT_CHILD|SPOUSE|SIBLING]->(b:Person)
+		RETURN a.id AS source, b.id AS target, type(rel) AS type,
+		       rel.start_date AS start_date, rel.end_date AS end_date, rel.end_reason AS end_reason
+	`
+
+	result, err := session.Run(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+	}
+
+	var links []models.Link
+	for result.Next(ctx) {
+		values := result.Record().AsMap()
+		source, _ := values["source"].(string)
+		target, _ := values["target"].(string)
+		relType, _ := values["type"].(string)
+		if source == "" || target == "" || relType == "" {
+			continue
+		}
+
+		links = append(links, models.Link{
+			Source:       source,
+			Target:       target,
+			Relationship: relType,
+			StartDate:    getStringPtrFromInterface(values["start_date"]),
+			EndDate:      getStringPtrFromInterface(values["end_date"]),
+			EndReason:    getStringPtrFromInterface(values["end_reason"]),
+		})
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+
+	return links, nil
+}
+
+// upsertBatchSize is the number of persons written per transaction
+const upsertBatchSize = 500
+
+// UpsertPersons creates or updates persons by ID in batched write transactions
+func (r *Neo4jRepository) UpsertPersons(ctx context.Context, persons []models.Person) ([]models.UpsertStatus, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	statuses := make([]models.UpsertStatus, 0, len(persons))
+	for start := 0; start < len(persons); start += upsertBatchSize {
+		batch := persons[start:min(start+upsertBatchSize, len(persons))]
+
+		result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			return upsertPersonBatch(ctx, tx, batch)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert persons %d-%d: %w", start+1, start+len(batch), classifyNeo4jError(err))
+		}
+		statuses = append(statuses, result.([]models.UpsertStatus)...)
+	}
+
+	return statuses, nil
+}
+
+// upsertPersonBatch compares a batch against the stored nodes and writes only
+// the persons that are new or changed
+func upsertPersonBatch(ctx context.Context, tx neo4j.ManagedTransaction, batch []models.Person) ([]models.UpsertStatus, error) {
+	ids := make([]string, len(batch))
+	for i, p := range batch {
+		ids[i] = p.ID
+	}
+
+	result, err := tx.Run(ctx, `MATCH (p:Person) WHERE p.id IN $ids RETURN p`, map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+	}
+
+	existing := make(map[string]models.Person)
+	for result.Next(ctx) {
+		if pVal, ok := result.Record().Get("p"); ok {
+			if node, ok := pVal.(neo4j.Node); ok {
+				person := nodeToModel(node)
+				existing[person.ID] = person
+			}
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+
+	statuses := make([]models.UpsertStatus, len(batch))
+	var writes []map[string]interface{}
+	for i, person := range batch {
+		stored, found := existing[person.ID]
+		switch {
+		case !found:
+			statuses[i] = models.UpsertCreated
+		case samePerson(stored, person):
+			statuses[i] = models.UpsertUnchanged
+			continue
+		default:
+			statuses[i] = models.UpsertUpdated
+		}
+		// Later rows with the same ID compare against this version
+		existing[person.ID] = person
+		writes = append(writes, personToProps(person))
+	}
+
+	if len(writes) == 0 {
+		return statuses, nil
+	}
+
+	query := `
+		UNWIND $persons AS person
+		MERGE (p:Person {id: person.id})
+		SET p.name = person.name,
+		    p.aka = person.aka,
+		    p.gender = person.gender,
+		    p.is_alive = person.is_alive,
+		    p.birth_date = person.birth_date,
+		    p.death_date = person.death_date,
+		    p.current_location = person.current_location,
+		    p.profession = person.profession,
+		    p.photo_url = person.photo_url,
+		    p.birth_place = person.birth_place,
+		    p.death_place = person.death_place
+	`
+	if _, err := tx.Run(ctx, query, map[string]interface{}{"persons": writes}); err != nil {
+		return nil, fmt.Errorf("failed to write persons: %w", classifyNeo4jError(err))
+	}
+
+	return statuses, nil
+}
+
+// ExistingPersonIDs reports which of the given IDs belong to stored persons
+func (r *Neo4jRepository) ExistingPersonIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	query := `MATCH (p:Person) WHERE p.id IN $ids RETURN p.id AS id`
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+	}
+
+	existing := make(map[string]bool)
+	for result.Next(ctx) {
+		if idVal, ok := result.Record().Get("id"); ok {
+			if id, ok := idVal.(string); ok {
+				existing[id] = true
+			}
+		}
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+
+	return existing, nil
+}
+
+// UpsertRelationships creates relationships or updates their dates in
+// batched write transactions. PARENT_CHILD links that would make someone
+// their own ancestor are skipped.
+func (r *Neo4jRepository) UpsertRelationships(ctx context.Context, links []models.Link) ([]models.UpsertStatus, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	statuses := make([]models.UpsertStatus, 0, len(links))
+	for start := 0; start < len(links); start += upsertBatchSize {
+		batch := links[start:min(start+upsertBatchSize, len(links))]
+
+		result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			return upsertRelationshipBatch(ctx, tx, batch)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert relationships %d-%d: %w", start+1, start+len(batch), classifyNeo4jError(err))
+		}
+		statuses = append(statuses, result.([]models.UpsertStatus)...)
+	}
+
+	return statuses, nil
+}
+
+// upsertRelationshipBatch compares a batch against the stored relationships
+// and writes only the links that are new or have changed dates. New
+// PARENT_CHILD links are created one at a time, so that the cycle check of
+// each sees the links before it.
+func upsertRelationshipBatch(ctx context.Context, tx neo4j.ManagedTransaction, batch []models.Link) ([]models.UpsertStatus, error) {
+	params := make([]map[string]interface{}, len(batch))
+	for i, link := range batch {
+		params[i] = map[string]interface{}{
+			"source": link.Source,
+			"target": link.Target,
+			"type":   link.Relationship,
+		}
+	}
+
+	// PARENT_CHILD must match in the given direction, the others in either
+	query := `
+		UNWIND $rels AS rel
+		MATCH (a:Person {id: rel.source})-[r]-(b:Person {id: rel.target})
+		WHERE type(r) = rel.type AND (rel.type <> 'PARENT_CHILD' OR startNode(r) = a)
+		RETURN DISTINCT rel.source AS source, rel.target AS target, rel.type AS type,
+		       r.start_date AS start_date, r.end_date AS end_date, r.end_reason AS end_reason
+	`
+	result, err := tx.Run(ctx, query, map[string]interface{}{"rels": params})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+	}
+
+	existing := make(map[string]models.Link)
+	for result.Next(ctx) {
+		record := result.Record()
+		source, _ := record.Get("source")
+		target, _ := record.Get("target")
+		relType, _ := record.Get("type")
+		startDate, _ := record.Get("start_date")
+		endDate, _ := record.Get("end_date")
+		endReason, _ := record.Get("end_reason")
+
+		link := models.Link{
+			Source:       source.(string),
+			Target:       target.(string),
+			Relationship: relType.(string),
+			StartDate:    getStringPtrFromInterface(startDate),
+			EndDate:      getStringPtrFromInterface(endDate),
+			EndReason:    getStringPtrFromInterface(endReason),
+		}
+		existing[LinkKey(link)] = link
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+
+	statuses := make([]models.UpsertStatus, len(batch))
+	writes := make(map[string][]map[string]interface{})
+	for i, link := range batch {
+		stored, found := existing[LinkKey(link)]
+		switch {
+		case !found && link.Relationship == models.RelationshipParentChild:
+			cycle, err := createsCycle(ctx, tx, link)
+			if err != nil {
+				return nil, err
+			}
+			if cycle {
+				statuses[i] = models.UpsertFailed
+				continue
+			}
+			statuses[i] = models.UpsertCreated
+			if err := createRelationshipTx(ctx, tx, link); err != nil {
+				return nil, err
+			}
+			existing[LinkKey(link)] = link
+			continue
+		case !found:
+			statuses[i] = models.UpsertCreated
+		case sameLinkDetails(stored, link):
+			statuses[i] = models.UpsertUnchanged
+			continue
+		default:
+			statuses[i] = models.UpsertUpdated
+		}
+		// Later rows for the same relationship compare against this version
+		existing[LinkKey(link)] = link
+		writes[link.Relationship] = append(writes[link.Relationship], linkToParams(link))
+	}
+
+	for _, relType := range models.RelationshipTypes {
+		if len(writes[relType]) == 0 {
+			continue
+		}
+		query := `
+			UNWIND $rels AS rel
+			MATCH (a:Person {id: rel.source}), (b:Person {id: rel.target})
+			MERGE (a)` + relationshipPattern(relType) + `(b)
+			SET r.start_date = rel.start_date,
+			    r.end_date = rel.end_date,
+			    r.end_reason = rel.end_reason
+		`
+		if _, err := tx.Run(ctx, query, map[string]interface{}{"rels": writes[relType]}); err != nil {
+			return nil, fmt.Errorf("failed to write %s relationships: %w", relType, classifyNeo4jError(err))
+		}
+	}
+
+	return statuses, nil
+}
+
+// relationshipPattern returns the Cypher relationship pattern, bound to r,
+// for a relationship type. Only PARENT_CHILD is directed.
+func relationshipPattern(relType string) string {
+	if relType == models.RelationshipParentChild {
+		return "-[r:" + relType + "]->"
+	}
+	return "-[r:" + relType + "]-"
+}
+
+// linkToParams converts a Link model to query parameters
+func linkToParams(l models.Link) map[string]interface{} {
+	params := map[string]interface{}{
+		"source":     l.Source,
+		"target":     l.Target,
+		"start_date": nil,
+		"end_date":   nil,
+		"end_reason": nil,
+	}
+	if l.StartDate != nil {
+		params["start_date"] = *l.StartDate
+	}
+	if l.EndDate != nil {
+		params["end_date"] = *l.EndDate
+	}
+	if l.EndReason != nil {
+		params["end_reason"] = *l.EndReason
+	}
+	return params
+}
+
+// CreatePerson creates a new person node
+func (r *Neo4jRepository) CreatePerson(ctx context.Context, person models.Person) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, `MATCH (p:Person {id: $id}) RETURN count(p) AS count`,
+			map[string]interface{}{"id": person.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+		}
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+		}
+		if count, _ := record.Get("count"); count.(int64) > 0 {
+			return nil, fmt.Errorf("person with id %s: %w", person.ID, ErrAlreadyExists)
+		}
+
+		if _, err := tx.Run(ctx, `CREATE (p:Person) SET p = $props`,
+			map[string]interface{}{"props": personToProps(person)}); err != nil {
+			return nil, fmt.Errorf("failed to create person: %w", classifyNeo4jError(err))
+		}
+		return nil, nil
+	})
+
+	return classifyNeo4jError(err)
+}
+
+// UpdatePerson replaces all properties of an existing person node
+func (r *Neo4jRepository) UpdatePerson(ctx context.Context, person models.Person) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, `MATCH (p:Person {id: $id}) SET p = $props RETURN count(p) AS count`,
+			map[string]interface{}{"id": person.ID, "props": personToProps(person)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+		}
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+		}
+		if count, _ := record.Get("count"); count.(int64) == 0 {
+			return nil, fmt.Errorf("person with id %s: %w", person.ID, ErrNotFound)
+		}
+		return nil, nil
+	})
+
+	return classifyNeo4jError(err)
+}
+
+// DeletePerson deletes a person node together with its relationships
+func (r *Neo4jRepository) DeletePerson(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, `MATCH (p:Person {id: $id}) DETACH DELETE p`,
+			map[string]interface{}{"id": id})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+		}
+		summary, err := result.Consume(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+		}
+		if summary.Counters().NodesDeleted() == 0 {
+			return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+		}
+		return nil, nil
+	})
+
+	return classifyNeo4jError(err)
+}
+
+// CreateRelationship creates a relationship between two existing person nodes
+func (r *Neo4jRepository) CreateRelationship(ctx context.Context, link models.Link) error {
+	if err := checkRelationshipType(link.Relationship); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	params := linkToParams(link)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		for _, id := range []string{link.Source, link.Target} {
+			found, err := hasRows(ctx, tx, `MATCH (p:Person {id: $id}) RETURN p.id`, map[string]interface{}{"id": id})
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+			}
+		}
+
+		duplicate, err := hasRows(ctx, tx, `
+			MATCH (a:Person {id: $source})`+relationshipPattern(link.Relationship)+`(b:Person {id: $target})
+			RETURN type(r)
+		`, params)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate {
+			return nil, fmt.Errorf("%s relationship between %s and %s: %w", link.Relationship, link.Source, link.Target, ErrAlreadyExists)
+		}
+
+		if link.Relationship == models.RelationshipParentChild {
+			// The new parent must not already descend from the child
+			cycle, err := createsCycle(ctx, tx, link)
+			if err != nil {
+				return nil, err
+			}
+			if cycle {
+				return nil, fmt.Errorf("%s as parent of %s: %w", link.Source, link.Target, ErrCycle)
+			}
+		}
+
+		return nil, createRelationshipTx(ctx, tx, link)
+	})
+
+	return classifyNeo4jError(err)
+}
+
+// createsCycle reports whether a new PARENT_CHILD link would make its
+// source, the parent, a descendant of its own child
+func createsCycle(ctx context.Context, tx neo4j.ManagedTransaction, link models.Link) (bool, error) {
+	return hasRows(ctx, tx, `
+		MATCH (b:Person {id: $target})-[:PARENT_CHILD*]->(a:Person {id: $source})
+		RETURN a.id LIMIT 1
+	`, linkToParams(link))
+}
+
+// createRelationshipTx creates a relationship between two person nodes
+func createRelationshipTx(ctx context.Context, tx neo4j.ManagedTransaction, link models.Link) error {
+	query := `
+		MATCH (a:Person {id: $source}), (b:Person {id: $target})
+		CREATE (a)-[r:` + link.Relationship + `]->(b)
+		SET r.start_date = $start_date,
+		    r.end_date = $end_date,
+		    r.end_reason = $end_reason
+	`
+	if _, err := tx.Run(ctx, query, linkToParams(link)); err != nil {
+		return fmt.Errorf("failed to create relationship: %w", classifyNeo4jError(err))
+	}
+	return nil
+}
+
+// UpdateRelationship replaces the dates of an existing relationship
+func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, link models.Link) error {
+	if err := checkRelationshipType(link.Relationship); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+			MATCH (a:Person {id: $source})` + relationshipPattern(link.Relationship) + `(b:Person {id: $target})
+			SET r.start_date = $start_date,
+			    r.end_date = $end_date,
+			    r.end_reason = $end_reason
+			RETURN type(r)
+		`
+		found, err := hasRows(ctx, tx, query, linkToParams(link))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("%s relationship between %s and %s: %w", link.Relationship, link.Source, link.Target, ErrNotFound)
+		}
+		return nil, nil
+	})
+
+	return classifyNeo4jError(err)
+}
+
+// DeleteRelationship deletes a relationship between two person nodes
+func (r *Neo4jRepository) DeleteRelationship(ctx context.Context, relType, source, target string) error {
+	if err := checkRelationshipType(relType); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session := r.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: r.database})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+			MATCH (a:Person {id: $source})` + relationshipPattern(relType) + `(b:Person {id: $target})
+			DELETE r
+		`
+		result, err := tx.Run(ctx, query, map[string]interface{}{"source": source, "target": target})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+		}
+		summary, err := result.Consume(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+		}
+		if summary.Counters().RelationshipsDeleted() == 0 {
+			return nil, fmt.Errorf("%s relationship between %s and %s: %w", relType, source, target, ErrNotFound)
+		}
+		return nil, nil
+	})
+
+	return classifyNeo4jError(err)
+}
+
+// classifyNeo4jError marks timeouts, connection failures and rejected
+// statements with the matching sentinel error
+func classifyNeo4jError(err error) error {
+	if err == nil || classified(err) {
+		return err
+	}
+
+	var neoErr *neo4j.Neo4jError
+	var connErr *neo4j.ConnectivityError
+	var limitErr *neo4j.TransactionExecutionLimit
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case errors.As(err, &connErr), errors.As(err, &limitErr):
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	case errors.As(err, &neoErr):
+		switch {
+		case strings.HasSuffix(neoErr.Code, "TransactionTimedOut"):
+			return fmt.Errorf("%w: %w", ErrTimeout, err)
+		case neoErr.Classification() == "TransientError", neoErr.HasSecurityCode():
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		case neoErr.Classification() == "ClientError" && neoErr.Category() == "Statement":
+			return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+	}
+	return err
+}
+
+// hasRows reports whether a query run inside a transaction returns any rows
+func hasRows(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]interface{}) (bool, error) {
+	result, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", classifyNeo4jError(err))
+	}
+	found := result.Next(ctx)
+	if err := result.Err(); err != nil {
+		return false, fmt.Errorf("error processing results: %w", classifyNeo4jError(err))
+	}
+	return found, nil
+}
+
+// personToProps converts a Person model to Neo4j node properties
+func personToProps(p models.Person) map[string]interface{} {
+	aka := p.Aka
+	if aka == nil {
+		aka = []string{}
+	}
+
+	var deathDate interface{}
+	if p.DeathDate != nil {
+		deathDate = *p.DeathDate
+	}
+
+	return map[string]interface{}{
+		"id":               p.ID,
+		"name":             p.Name,
+		"aka":              aka,
+		"gender":           p.Gender,
+		"is_alive":         p.IsAlive,
+		"birth_date":       p.BirthDate,
+		"death_date":       deathDate,
+		"current_location": p.CurrentLocation,
+		"profession":       p.Profession,
+		"photo_url":        p.PhotoURL,
+		"birth_place":      p.BirthPlace,
+		"death_place":      p.DeathPlace,
+	}
+}
+
 // nodeToModel converts a Neo4j node to a Person model
 func nodeToModel(node neo4j.Node) models.Person {
 	props := node.Props
@@ -346,6 +1090,8 @@ func nodeToModel(node neo4j.Node) models.Person {
 		CurrentLocation: getStringProp(props, "current_location"),
 		Profession:      getStringProp(props, "profession"),
 		PhotoURL:        getStringProp(props, "photo_url"),
+		BirthPlace:      getStringProp(props, "birth_place"),
+		DeathPlace:      getStringProp(props, "death_place"),
 	}
 
 	return person
@@ -354,11 +1100,12 @@ func nodeToModel(node neo4j.Node) models.Person {
 // relationshipToModel converts a Neo4j relationship to a Link model
 func relationshipToModel(rel neo4j.Relationship) models.Link {
 	link := models.Link{
-		Source:       fmt.Sprintf("%d", rel.StartElementId),
-		Target:       fmt.Sprintf("%d", rel.EndElementId),
+		Source:       rel.StartElementId,
+		Target:       rel.EndElementId,
 		Relationship: rel.Type,
 		StartDate:    getDatePropPtr(rel.Props, "start_date"),
 		EndDate:      getDatePropPtr(rel.Props, "end_date"),
+		EndReason:    getStringPtrFromInterface(rel.Props["end_reason"]),
 	}
 	return link
 }
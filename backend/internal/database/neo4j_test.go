@@ -0,0 +1,35 @@
+package database_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/config"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+)
+
+// TestNeo4jRepository runs against a live server and deletes everything in
+// it. Set NEO4J_TEST_URI (plus NEO4J_USERNAME and NEO4J_PASSWORD) to run it.
+func TestNeo4jRepository(t *testing.T) {
+	uri := os.Getenv("NEO4J_TEST_URI")
+	if uri == "" {
+		t.Skip("NEO4J_TEST_URI not set")
+	}
+
+	cfg := config.Load()
+	cfg.Neo4jURI = uri
+
+	repotest.Run(t, func(t *testing.T) database.Repository {
+		repo, err := database.NewNeo4jRepository(cfg)
+		if err != nil {
+			t.Fatalf("NewNeo4jRepository: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+
+		if err := repo.DeleteAll(); err != nil {
+			t.Fatalf("DeleteAll: %v", err)
+		}
+		return repo
+	})
+}
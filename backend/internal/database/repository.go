@@ -1,6 +1,9 @@
 package database
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/heemankverma/family_tree/backend/internal/config"
 	"github.com/heemankverma/family_tree/backend/internal/models"
 )
@@ -8,25 +11,75 @@ import (
 // Repository defines the interface for database operations
 type Repository interface {
 	// GetTreeData returns nodes and links for tree visualization
-	GetTreeData(centerNodeID string, depth int) (*models.TreeResponse, error)
+	GetTreeData(ctx context.Context, centerNodeID string, depth int) (*models.TreeResponse, error)
 
-	// GetPersonByID returns a person by their ID
-	GetPersonByID(id string) (*models.Person, error)
+	// GetPersonByID returns a person by their ID, or ErrNotFound
+	GetPersonByID(ctx context.Context, id string) (*models.Person, error)
 
 	// GetImmediateFamily returns the immediate family of a person
-	GetImmediateFamily(id string) (*models.ImmediateFamily, error)
+	GetImmediateFamily(ctx context.Context, id string) (*models.ImmediateFamily, error)
 
-	// ExecuteQuery executes a raw Cypher query (read-only)
-	ExecuteQuery(query string) (*models.QueryResponse, error)
+	// ExecuteQuery executes a raw Cypher query (read-only). Backends without
+	// Cypher support return ErrNotSupported.
+	ExecuteQuery(ctx context.Context, query string) (*models.QueryResponse, error)
 
 	// GetAllPersons returns all persons in the database
-	GetAllPersons() ([]models.Person, error)
+	GetAllPersons(ctx context.Context) ([]models.Person, error)
+
+	// GetAllRelationships returns every relationship in the database.
+	// PARENT_CHILD links point from parent to child.
+	GetAllRelationships(ctx context.Context) ([]models.Link, error)
+
+	// UpsertPersons creates or updates persons by ID and reports, for each
+	// input person in order, whether it was created, updated or unchanged
+	UpsertPersons(ctx context.Context, persons []models.Person) ([]models.UpsertStatus, error)
+
+	// ExistingPersonIDs reports which of the given IDs belong to stored persons
+	ExistingPersonIDs(ctx context.Context, ids []string) (map[string]bool, error)
+
+	// UpsertRelationships creates relationships or updates their dates and
+	// reports the outcome for each input link in order. Both endpoints of
+	// every link must already exist. A new PARENT_CHILD link that would make
+	// someone their own ancestor is not written and reported as UpsertFailed.
+	UpsertRelationships(ctx context.Context, links []models.Link) ([]models.UpsertStatus, error)
+
+	// CreatePerson creates a new person, or returns ErrAlreadyExists
+	CreatePerson(ctx context.Context, person models.Person) error
+
+	// UpdatePerson replaces all fields of an existing person, or returns ErrNotFound
+	UpdatePerson(ctx context.Context, person models.Person) error
+
+	// DeletePerson deletes a person and all of their relationships, or returns ErrNotFound
+	DeletePerson(ctx context.Context, id string) error
+
+	// CreateRelationship creates a relationship between two existing persons.
+	// It returns ErrNotFound if either person is missing, ErrAlreadyExists if
+	// the relationship exists and ErrCycle if a PARENT_CHILD link would make
+	// someone their own ancestor.
+	CreateRelationship(ctx context.Context, link models.Link) error
+
+	// UpdateRelationship replaces the dates of an existing relationship, or
+	// returns ErrNotFound
+	UpdateRelationship(ctx context.Context, link models.Link) error
+
+	// DeleteRelationship deletes the relationship of the given type between
+	// source and target, or returns ErrNotFound. Only PARENT_CHILD is directed.
+	DeleteRelationship(ctx context.Context, relType, source, target string) error
 
 	// Close closes the database connection
 	Close() error
 }
 
-// NewRepository creates a new Neo4j repository
+// NewRepository creates the repository for the configured storage backend
 func NewRepository(cfg *config.Config) (Repository, error) {
-	return NewNeo4jRepository(cfg)
+	switch cfg.StorageBackend {
+	case config.StorageNeo4j:
+		return NewNeo4jRepository(cfg)
+	case config.StorageMemory:
+		return NewMemoryRepositoryFromCSV(cfg.SeedPersonsCSV, cfg.SeedRelationshipsCSV)
+	case config.StorageSQLite:
+		return NewSQLiteRepository(cfg.SQLitePath, TimeoutsFromConfig(cfg))
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
 }
@@ -0,0 +1,720 @@
+// Package repotest is a conformance test suite that every implementation of
+// database.Repository must pass. Backends call Run from their own tests with
+// a factory that returns an empty repository.
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Factory returns an empty repository for a single test. It should register
+// any cleanup with t.Cleanup.
+type Factory func(t *testing.T) database.Repository
+
+// Fixtures are the persons and relationships of data/example_*.csv
+type Fixtures struct {
+	Persons []models.Person
+	Links   []models.Link
+}
+
+// Run runs the conformance suite. Every subtest gets a fresh repository.
+func Run(t *testing.T, newRepo Factory) {
+	fixtures := LoadFixtures(t)
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures)
+	}{
+		{"GetPersonByID", testGetPersonByID},
+		{"GetAllPersons", testGetAllPersons},
+		{"GetAllRelationships", testGetAllRelationships},
+		{"TreeDepth", testTreeDepth},
+		{"TreeDepthClamp", testTreeDepthClamp},
+		{"TreeMissingCenter", testTreeMissingCenter},
+		{"ParentChildDirection", testParentChildDirection},
+		{"SiblingSpouseSymmetry", testSiblingSpouseSymmetry},
+		{"DerivedKin", testDerivedKin},
+		{"Partnerships", testPartnerships},
+		{"MissingPerson", testMissingPerson},
+		{"UpsertPersons", testUpsertPersons},
+		{"ExistingPersonIDs", testExistingPersonIDs},
+		{"UpsertRelationships", testUpsertRelationships},
+		{"UpsertRelationshipCycle", testUpsertRelationshipCycle},
+		{"PersonWrites", testPersonWrites},
+		{"CreateRelationship", testCreateRelationship},
+		{"UpdateDeleteRelationship", testUpdateDeleteRelationship},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			repo := newRepo(t)
+			Seed(t, ctx, repo, fixtures)
+			tt.fn(t, ctx, repo, fixtures)
+		})
+	}
+}
+
+// LoadFixtures reads data/example_persons.csv and data/example_relationships.csv
+func LoadFixtures(t testing.TB) Fixtures {
+	t.Helper()
+
+	_, file, _, _ := runtime.Caller(0)
+	dataDir := filepath.Join(filepath.Dir(file), "..", "..", "..", "..", "data")
+
+	var fx Fixtures
+
+	pf, err := os.Open(filepath.Join(dataDir, "example_persons.csv"))
+	if err != nil {
+		t.Fatalf("open persons fixture: %v", err)
+	}
+	defer pf.Close()
+
+	personRows, err := csvdata.ReadPersons(pf)
+	if err != nil {
+		t.Fatalf("read persons fixture: %v", err)
+	}
+	for _, row := range personRows {
+		if row.Err != nil {
+			t.Fatalf("persons fixture row %d: %v", row.Row, row.Err)
+		}
+		fx.Persons = append(fx.Persons, row.Person)
+	}
+
+	rf, err := os.Open(filepath.Join(dataDir, "example_relationships.csv"))
+	if err != nil {
+		t.Fatalf("open relationships fixture: %v", err)
+	}
+	defer rf.Close()
+
+	linkRows, err := csvdata.ReadRelationships(rf)
+	if err != nil {
+		t.Fatalf("read relationships fixture: %v", err)
+	}
+	for _, row := range linkRows {
+		if row.Err != nil {
+			t.Fatalf("relationships fixture row %d: %v", row.Row, row.Err)
+		}
+		fx.Links = append(fx.Links, row.Link)
+	}
+
+	return fx
+}
+
+// Seed writes the fixtures into an empty repository
+func Seed(t testing.TB, ctx context.Context, repo database.Repository, fx Fixtures) {
+	t.Helper()
+
+	if _, err := repo.UpsertPersons(ctx, fx.Persons); err != nil {
+		t.Fatalf("seed persons: %v", err)
+	}
+	if _, err := repo.UpsertRelationships(ctx, fx.Links); err != nil {
+		t.Fatalf("seed relationships: %v", err)
+	}
+}
+
+func testGetPersonByID(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	for _, want := range fx.Persons {
+		got, err := repo.GetPersonByID(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("GetPersonByID(%s): %v", want.ID, err)
+		}
+		assertSamePerson(t, want, *got)
+	}
+}
+
+func testGetAllPersons(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	persons, err := repo.GetAllPersons(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPersons: %v", err)
+	}
+	assertSameIDs(t, "GetAllPersons", personIDs(fx.Persons), personIDs(persons))
+}
+
+func testGetAllRelationships(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	links, err := repo.GetAllRelationships(ctx)
+	if err != nil {
+		t.Fatalf("GetAllRelationships: %v", err)
+	}
+
+	var want, got []string
+	for _, link := range fx.Links {
+		want = append(want, undirectedKey(link))
+	}
+	for _, link := range links {
+		got = append(got, undirectedKey(link))
+	}
+	assertSameIDs(t, "GetAllRelationships", want, got)
+}
+
+func testTreeDepth(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	for _, center := range []string{"me-001", "ggp-001", "child-005", "spouse-001"} {
+		for depth := 1; depth <= 3; depth++ {
+			tree, err := repo.GetTreeData(ctx, center, depth)
+			if err != nil {
+				t.Fatalf("GetTreeData(%s, %d): %v", center, depth, err)
+			}
+
+			reach := reachable(fx.Links, center, depth)
+			assertSameIDs(t, "nodes", keys(reach), personIDs(tree.Nodes))
+
+			var wantLinks []string
+			for _, link := range fx.Links {
+				if reach[link.Source] && reach[link.Target] {
+					wantLinks = append(wantLinks, undirectedKey(link))
+				}
+			}
+			var gotLinks []string
+			for _, link := range tree.Links {
+				gotLinks = append(gotLinks, undirectedKey(link))
+			}
+			assertSameIDs(t, "links", wantLinks, gotLinks)
+		}
+	}
+}
+
+func testTreeDepthClamp(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	for _, tc := range []struct{ depth, effective int }{{0, 2}, {-1, 2}, {4, 3}, {10, 3}} {
+		tree, err := repo.GetTreeData(ctx, "me-001", tc.depth)
+		if err != nil {
+			t.Fatalf("GetTreeData(me-001, %d): %v", tc.depth, err)
+		}
+		want := reachable(fx.Links, "me-001", tc.effective)
+		assertSameIDs(t, "nodes", keys(want), personIDs(tree.Nodes))
+	}
+}
+
+func testTreeMissingCenter(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	tree, err := repo.GetTreeData(ctx, "nobody", 2)
+	if err != nil {
+		t.Fatalf("GetTreeData(nobody): %v", err)
+	}
+	if len(tree.Nodes) != 0 || len(tree.Links) != 0 {
+		t.Errorf("GetTreeData(nobody) = %d nodes, %d links; want none", len(tree.Nodes), len(tree.Links))
+	}
+}
+
+func testParentChildDirection(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	tree, err := repo.GetTreeData(ctx, "me-001", 1)
+	if err != nil {
+		t.Fatalf("GetTreeData: %v", err)
+	}
+	for _, link := range tree.Links {
+		if link.Relationship != models.RelationshipParentChild {
+			continue
+		}
+		if !slices.ContainsFunc(fx.Links, func(want models.Link) bool {
+			return want.Relationship == link.Relationship && want.Source == link.Source && want.Target == link.Target
+		}) {
+			t.Errorf("PARENT_CHILD link %s -> %s is not in the fixtures (reversed?)", link.Source, link.Target)
+		}
+	}
+
+	family, err := repo.GetImmediateFamily(ctx, "me-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	assertSameIDs(t, "parents", []string{"dad-001", "mom-001"}, personIDs(family.Parents))
+	assertSameIDs(t, "children", []string{"child-001", "child-002"}, personIDs(family.Children))
+
+	family, err = repo.GetImmediateFamily(ctx, "child-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	assertSameIDs(t, "parents", []string{"me-001", "spouse-001"}, personIDs(family.Parents))
+	assertSameIDs(t, "children", nil, personIDs(family.Children))
+}
+
+func testSiblingSpouseSymmetry(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	families := make(map[string]*models.ImmediateFamily)
+	for _, p := range fx.Persons {
+		family, err := repo.GetImmediateFamily(ctx, p.ID)
+		if err != nil {
+			t.Fatalf("GetImmediateFamily(%s): %v", p.ID, err)
+		}
+		families[p.ID] = family
+	}
+
+	for _, link := range fx.Links {
+		a, b := link.Source, link.Target
+		switch link.Relationship {
+		case models.RelationshipSibling:
+			for _, pair := range [][2]string{{a, b}, {b, a}} {
+				if !slices.Contains(personIDs(families[pair[0]].Siblings), pair[1]) {
+					t.Errorf("siblings of %s do not include %s", pair[0], pair[1])
+				}
+			}
+		case models.RelationshipSpouse:
+			for _, pair := range [][2]string{{a, b}, {b, a}} {
+				if partners := partnerIDs(families[pair[0]].Partnerships); !slices.Equal(partners, []string{pair[1]}) {
+					t.Errorf("partners of %s = %v; want [%s]", pair[0], partners, pair[1])
+				}
+			}
+		}
+	}
+}
+
+func testDerivedKin(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	// dad-001 remarries step-001, who has step-002 from an earlier
+	// marriage; they have half-001 together
+	for _, p := range []models.Person{
+		{ID: "step-001", Name: "Step Mother", Gender: "Female", BirthDate: "1960-01-01"},
+		{ID: "step-002", Name: "Step Brother", Gender: "Male", BirthDate: "1984-01-01"},
+		{ID: "half-001", Name: "Half Sister", Gender: "Female", BirthDate: "1995-01-01"},
+	} {
+		if err := repo.CreatePerson(ctx, p); err != nil {
+			t.Fatalf("CreatePerson(%s): %v", p.ID, err)
+		}
+	}
+	for _, link := range []models.Link{
+		{Relationship: models.RelationshipSpouse, Source: "dad-001", Target: "step-001"},
+		{Relationship: models.RelationshipParentChild, Source: "step-001", Target: "step-002"},
+		{Relationship: models.RelationshipParentChild, Source: "dad-001", Target: "half-001"},
+		{Relationship: models.RelationshipParentChild, Source: "step-001", Target: "half-001"},
+	} {
+		if err := repo.CreateRelationship(ctx, link); err != nil {
+			t.Fatalf("CreateRelationship(%s %s -> %s): %v", link.Relationship, link.Source, link.Target, err)
+		}
+	}
+
+	tests := []struct {
+		id                               string
+		full, half, stepSibs, stepParent []string
+	}{
+		{"me-001", []string{"sibling-001", "sibling-002"}, []string{"half-001"}, []string{"step-002"}, []string{"step-001"}},
+		{"half-001", nil, []string{"me-001", "sibling-001", "sibling-002", "step-002"}, nil, []string{"mom-001"}},
+		{"step-002", nil, []string{"half-001"}, []string{"me-001", "sibling-001", "sibling-002"}, []string{"dad-001"}},
+		{"cousin-001", []string{"cousin-002"}, nil, nil, nil},
+		{"gp-001", nil, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		family, err := repo.GetImmediateFamily(ctx, tt.id)
+		if err != nil {
+			t.Fatalf("GetImmediateFamily(%s): %v", tt.id, err)
+		}
+		if family.FullSiblings == nil || family.HalfSiblings == nil || family.StepSiblings == nil || family.StepParents == nil {
+			t.Errorf("%s: derived lists must not be nil", tt.id)
+		}
+		assertSameIDs(t, tt.id+" full siblings", tt.full, personIDs(family.FullSiblings))
+		assertSameIDs(t, tt.id+" half siblings", tt.half, personIDs(family.HalfSiblings))
+		assertSameIDs(t, tt.id+" step-siblings", tt.stepSibs, personIDs(family.StepSiblings))
+		assertSameIDs(t, tt.id+" step-parents", tt.stepParent, personIDs(family.StepParents))
+	}
+}
+
+func testPartnerships(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	// me-001 was married to ex-001 before spouse-001
+	start, end, divorce := "2005-03-01", "2009-11-30", models.EndReasonDivorce
+	if err := repo.CreatePerson(ctx, models.Person{ID: "ex-001", Name: "Former Spouse", Gender: "Female", IsAlive: true, BirthDate: "1984-01-01"}); err != nil {
+		t.Fatalf("CreatePerson: %v", err)
+	}
+	marriage := models.Link{Relationship: models.RelationshipSpouse, Source: "ex-001", Target: "me-001", StartDate: &start, EndDate: &end, EndReason: &divorce}
+	if err := repo.CreateRelationship(ctx, marriage); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+
+	links, err := repo.GetAllRelationships(ctx)
+	if err != nil {
+		t.Fatalf("GetAllRelationships: %v", err)
+	}
+	for _, link := range links {
+		if undirectedKey(link) == undirectedKey(marriage) && fmtDate(link.EndReason) != divorce {
+			t.Errorf("end_reason = %s; want %s", fmtDate(link.EndReason), divorce)
+		}
+	}
+
+	family, err := repo.GetImmediateFamily(ctx, "me-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	if partners := partnerIDs(family.Partnerships); !slices.Equal(partners, []string{"ex-001", "spouse-001"}) {
+		t.Fatalf("partners of me-001 = %v; want [ex-001 spouse-001] in order", partners)
+	}
+	ex, current := family.Partnerships[0], family.Partnerships[1]
+	if fmtDate(ex.StartDate) != start || fmtDate(ex.EndDate) != end || fmtDate(ex.EndReason) != divorce || ex.Current {
+		t.Errorf("ex-001 partnership = %s to %s (%s), current %v; want %s to %s (%s), not current",
+			fmtDate(ex.StartDate), fmtDate(ex.EndDate), fmtDate(ex.EndReason), ex.Current, start, end, divorce)
+	}
+	if !current.Current || current.EndDate != nil || current.EndReason != nil {
+		t.Errorf("spouse-001 partnership current %v, ended %s (%s); want current", current.Current, fmtDate(current.EndDate), fmtDate(current.EndReason))
+	}
+
+	// A marriage without an end ends when the first partner dies
+	family, err = repo.GetImmediateFamily(ctx, "gm-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	if len(family.Partnerships) != 1 {
+		t.Fatalf("partners of gm-001 = %v; want [gp-001]", partnerIDs(family.Partnerships))
+	}
+	widowed := family.Partnerships[0]
+	if fmtDate(widowed.EndReason) != models.EndReasonDeath || fmtDate(widowed.EndDate) != "2010-08-20" || widowed.Current {
+		t.Errorf("gp-001 partnership ended %s (%s), current %v; want 2010-08-20 (death), not current",
+			fmtDate(widowed.EndDate), fmtDate(widowed.EndReason), widowed.Current)
+	}
+
+	// Persons stored without is_alive are not taken to have died
+	for _, id := range []string{"np-001", "np-002"} {
+		if err := repo.CreatePerson(ctx, models.Person{ID: id, Name: "No Status " + id, Gender: "Other", BirthDate: "1990-01-01"}); err != nil {
+			t.Fatalf("CreatePerson: %v", err)
+		}
+	}
+	if err := repo.CreateRelationship(ctx, models.Link{Relationship: models.RelationshipSpouse, Source: "np-001", Target: "np-002"}); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	family, err = repo.GetImmediateFamily(ctx, "np-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	if len(family.Partnerships) != 1 || !family.Partnerships[0].Current || family.Partnerships[0].EndReason != nil {
+		t.Errorf("partnerships of np-001 = %+v; want np-002, current", family.Partnerships)
+	}
+}
+
+func testMissingPerson(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	if _, err := repo.GetPersonByID(ctx, "nobody"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("GetPersonByID(nobody) error = %v; want ErrNotFound", err)
+	}
+	if _, err := repo.GetImmediateFamily(ctx, "nobody"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("GetImmediateFamily(nobody) error = %v; want ErrNotFound", err)
+	}
+}
+
+func testUpsertPersons(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	statuses, err := repo.UpsertPersons(ctx, fx.Persons)
+	if err != nil {
+		t.Fatalf("UpsertPersons: %v", err)
+	}
+	for i, status := range statuses {
+		if status != models.UpsertUnchanged {
+			t.Errorf("re-upsert %s = %s; want unchanged", fx.Persons[i].ID, status)
+		}
+	}
+
+	changed := fx.Persons[0]
+	changed.Profession = "Astronaut"
+	changed.BirthPlace = "Salem USA"
+	added := models.Person{ID: "new-001", Name: "New Person", Aka: []string{}, Gender: "Other", BirthDate: "2000-01-01"}
+	addedAgain := added
+	addedAgain.Name = "Renamed Person"
+
+	statuses, err = repo.UpsertPersons(ctx, []models.Person{changed, added, addedAgain})
+	if err != nil {
+		t.Fatalf("UpsertPersons: %v", err)
+	}
+	want := []models.UpsertStatus{models.UpsertUpdated, models.UpsertCreated, models.UpsertUpdated}
+	if !slices.Equal(statuses, want) {
+		t.Errorf("UpsertPersons statuses = %v; want %v", statuses, want)
+	}
+
+	got, err := repo.GetPersonByID(ctx, changed.ID)
+	if err != nil {
+		t.Fatalf("GetPersonByID: %v", err)
+	}
+	assertSamePerson(t, changed, *got)
+
+	got, err = repo.GetPersonByID(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("GetPersonByID: %v", err)
+	}
+	assertSamePerson(t, addedAgain, *got)
+}
+
+func testExistingPersonIDs(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	existing, err := repo.ExistingPersonIDs(ctx, []string{"me-001", "nobody", "dad-001"})
+	if err != nil {
+		t.Fatalf("ExistingPersonIDs: %v", err)
+	}
+	if !existing["me-001"] || !existing["dad-001"] || existing["nobody"] {
+		t.Errorf("ExistingPersonIDs = %v; want me-001 and dad-001 only", existing)
+	}
+}
+
+func testUpsertRelationships(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	statuses, err := repo.UpsertRelationships(ctx, fx.Links)
+	if err != nil {
+		t.Fatalf("UpsertRelationships: %v", err)
+	}
+	for i, status := range statuses {
+		if status != models.UpsertUnchanged {
+			t.Errorf("re-upsert %s = %s; want unchanged", undirectedKey(fx.Links[i]), status)
+		}
+	}
+
+	date := "2001-02-03"
+	links := []models.Link{
+		// Undirected links match in either direction
+		{Relationship: models.RelationshipSpouse, Source: "spouse-001", Target: "me-001", StartDate: &date},
+		{Relationship: models.RelationshipSibling, Source: "sibling-002", Target: "me-001"},
+		{Relationship: models.RelationshipSibling, Source: "cousin-001", Target: "cousin-002"},
+		// The reverse of a PARENT_CHILD link is a different relationship
+		{Relationship: models.RelationshipParentChild, Source: "dad-001", Target: "me-001"},
+	}
+	statuses, err = repo.UpsertRelationships(ctx, links)
+	if err != nil {
+		t.Fatalf("UpsertRelationships: %v", err)
+	}
+	want := []models.UpsertStatus{models.UpsertUpdated, models.UpsertUnchanged, models.UpsertCreated, models.UpsertUnchanged}
+	if !slices.Equal(statuses, want) {
+		t.Errorf("UpsertRelationships statuses = %v; want %v", statuses, want)
+	}
+
+	tree, err := repo.GetTreeData(ctx, "me-001", 1)
+	if err != nil {
+		t.Fatalf("GetTreeData: %v", err)
+	}
+	for _, link := range tree.Links {
+		if link.Relationship == models.RelationshipSpouse && link.StartDate != nil && *link.StartDate == date {
+			return
+		}
+	}
+	t.Errorf("updated spouse start_date %s not returned by GetTreeData", date)
+}
+
+func testUpsertRelationshipCycle(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	person := fx.Persons[0]
+	person.ID, person.Name = "grandchild-001", "Grandchild"
+	if _, err := repo.UpsertPersons(ctx, []models.Person{person}); err != nil {
+		t.Fatalf("UpsertPersons: %v", err)
+	}
+
+	links := []models.Link{
+		{Relationship: models.RelationshipParentChild, Source: "child-001", Target: "me-001"},
+		{Relationship: models.RelationshipParentChild, Source: "child-001", Target: "grandchild-001"},
+		// Only a cycle because of the row before it
+		{Relationship: models.RelationshipParentChild, Source: "grandchild-001", Target: "child-001"},
+		{Relationship: models.RelationshipSibling, Source: "child-001", Target: "me-001"},
+	}
+	statuses, err := repo.UpsertRelationships(ctx, links)
+	if err != nil {
+		t.Fatalf("UpsertRelationships: %v", err)
+	}
+	want := []models.UpsertStatus{models.UpsertFailed, models.UpsertCreated, models.UpsertFailed, models.UpsertCreated}
+	if !slices.Equal(statuses, want) {
+		t.Errorf("UpsertRelationships statuses = %v; want %v", statuses, want)
+	}
+
+	stored, err := repo.GetAllRelationships(ctx)
+	if err != nil {
+		t.Fatalf("GetAllRelationships: %v", err)
+	}
+	for _, link := range stored {
+		if link.Relationship == models.RelationshipParentChild && link.Source == "grandchild-001" {
+			t.Errorf("cyclic link %s -> %s was written", link.Source, link.Target)
+		}
+		if link.Relationship == models.RelationshipParentChild && link.Source == "child-001" && link.Target == "me-001" {
+			t.Errorf("cyclic link %s -> %s was written", link.Source, link.Target)
+		}
+	}
+}
+
+func testPersonWrites(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	if err := repo.CreatePerson(ctx, fx.Persons[0]); !errors.Is(err, database.ErrAlreadyExists) {
+		t.Errorf("CreatePerson(existing) error = %v; want ErrAlreadyExists", err)
+	}
+
+	deathDate := "2020-05-05"
+	person := models.Person{ID: "new-001", Name: "New Person", Aka: []string{"Newbie"}, Gender: "Female", BirthDate: "1940-01-01"}
+	if err := repo.CreatePerson(ctx, person); err != nil {
+		t.Fatalf("CreatePerson: %v", err)
+	}
+
+	person.DeathDate = &deathDate
+	person.Aka = []string{}
+	if err := repo.UpdatePerson(ctx, person); err != nil {
+		t.Fatalf("UpdatePerson: %v", err)
+	}
+	got, err := repo.GetPersonByID(ctx, person.ID)
+	if err != nil {
+		t.Fatalf("GetPersonByID: %v", err)
+	}
+	assertSamePerson(t, person, *got)
+
+	if err := repo.UpdatePerson(ctx, models.Person{ID: "nobody", Name: "Nobody"}); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("UpdatePerson(nobody) error = %v; want ErrNotFound", err)
+	}
+
+	// Deleting a person removes their relationships too
+	if err := repo.DeletePerson(ctx, "spouse-001"); err != nil {
+		t.Fatalf("DeletePerson: %v", err)
+	}
+	if _, err := repo.GetPersonByID(ctx, "spouse-001"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("GetPersonByID(deleted) error = %v; want ErrNotFound", err)
+	}
+	family, err := repo.GetImmediateFamily(ctx, "me-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	if partners := partnerIDs(family.Partnerships); len(partners) != 0 {
+		t.Errorf("partners of me-001 = %v after deleting spouse-001; want none", partners)
+	}
+	if err := repo.DeletePerson(ctx, "spouse-001"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeletePerson(deleted) error = %v; want ErrNotFound", err)
+	}
+}
+
+func testCreateRelationship(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	cases := []struct {
+		name string
+		link models.Link
+		want error
+	}{
+		{"missing person", models.Link{Relationship: models.RelationshipSibling, Source: "me-001", Target: "nobody"}, database.ErrNotFound},
+		{"duplicate", models.Link{Relationship: models.RelationshipParentChild, Source: "dad-001", Target: "me-001"}, database.ErrAlreadyExists},
+		{"reversed undirected duplicate", models.Link{Relationship: models.RelationshipSpouse, Source: "spouse-001", Target: "me-001"}, database.ErrAlreadyExists},
+		{"child as parent", models.Link{Relationship: models.RelationshipParentChild, Source: "me-001", Target: "dad-001"}, database.ErrCycle},
+		{"descendant as ancestor", models.Link{Relationship: models.RelationshipParentChild, Source: "child-001", Target: "ggp-001"}, database.ErrCycle},
+	}
+	for _, tc := range cases {
+		if err := repo.CreateRelationship(ctx, tc.link); !errors.Is(err, tc.want) {
+			t.Errorf("%s: CreateRelationship error = %v; want %v", tc.name, err, tc.want)
+		}
+	}
+
+	link := models.Link{Relationship: models.RelationshipParentChild, Source: "uncle-003", Target: "child-003"}
+	if err := repo.CreateRelationship(ctx, link); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	family, err := repo.GetImmediateFamily(ctx, "child-003")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	assertSameIDs(t, "parents", []string{"cousin-001", "uncle-003"}, personIDs(family.Parents))
+}
+
+func testUpdateDeleteRelationship(t *testing.T, ctx context.Context, repo database.Repository, fx Fixtures) {
+	endDate := "2020-01-01"
+	update := models.Link{Relationship: models.RelationshipSpouse, Source: "spouse-001", Target: "me-001", EndDate: &endDate}
+	if err := repo.UpdateRelationship(ctx, update); err != nil {
+		t.Fatalf("UpdateRelationship: %v", err)
+	}
+
+	tree, err := repo.GetTreeData(ctx, "me-001", 1)
+	if err != nil {
+		t.Fatalf("GetTreeData: %v", err)
+	}
+	for _, link := range tree.Links {
+		if undirectedKey(link) == undirectedKey(update) {
+			if link.StartDate != nil || link.EndDate == nil || *link.EndDate != endDate {
+				t.Errorf("spouse link dates = %s, %s; want cleared start and end %s", fmtDate(link.StartDate), fmtDate(link.EndDate), endDate)
+			}
+		}
+	}
+
+	missing := models.Link{Relationship: models.RelationshipSpouse, Source: "me-001", Target: "dad-001"}
+	if err := repo.UpdateRelationship(ctx, missing); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("UpdateRelationship(missing) error = %v; want ErrNotFound", err)
+	}
+	if err := repo.DeleteRelationship(ctx, models.RelationshipParentChild, "me-001", "dad-001"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeleteRelationship(reversed PARENT_CHILD) error = %v; want ErrNotFound", err)
+	}
+	if err := repo.DeleteRelationship(ctx, "FRIEND", "me-001", "dad-001"); !errors.Is(err, database.ErrInvalidInput) {
+		t.Errorf("DeleteRelationship(FRIEND) error = %v; want ErrInvalidInput", err)
+	}
+
+	if err := repo.DeleteRelationship(ctx, models.RelationshipSibling, "sibling-001", "me-001"); err != nil {
+		t.Fatalf("DeleteRelationship: %v", err)
+	}
+	family, err := repo.GetImmediateFamily(ctx, "me-001")
+	if err != nil {
+		t.Fatalf("GetImmediateFamily: %v", err)
+	}
+	assertSameIDs(t, "siblings", []string{"sibling-002"}, personIDs(family.Siblings))
+}
+
+// reachable returns the IDs within depth hops of center over every link in
+// either direction, as a reference for GetTreeData
+func reachable(links []models.Link, center string, depth int) map[string]bool {
+	reach := map[string]bool{center: true}
+	frontier := []string{center}
+	for level := 0; level < depth; level++ {
+		var next []string
+		for _, id := range frontier {
+			for _, link := range links {
+				for _, pair := range [][2]string{{link.Source, link.Target}, {link.Target, link.Source}} {
+					if pair[0] == id && !reach[pair[1]] {
+						reach[pair[1]] = true
+						next = append(next, pair[1])
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+	return reach
+}
+
+// undirectedKey identifies a link, ignoring direction except for PARENT_CHILD
+func undirectedKey(l models.Link) string {
+	source, target := l.Source, l.Target
+	if l.Relationship != models.RelationshipParentChild && target < source {
+		source, target = target, source
+	}
+	return l.Relationship + ":" + source + ":" + target
+}
+
+func fmtDate(date *string) string {
+	if date == nil {
+		return "<nil>"
+	}
+	return *date
+}
+
+func personIDs(persons []models.Person) []string {
+	ids := make([]string, len(persons))
+	for i, p := range persons {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func partnerIDs(partnerships []models.Partnership) []string {
+	ids := make([]string, 0, len(partnerships))
+	for _, p := range partnerships {
+		ids = append(ids, p.Partner.ID)
+	}
+	return ids
+}
+
+func keys(set map[string]bool) []string {
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func assertSameIDs(t *testing.T, what string, want, got []string) {
+	t.Helper()
+	want, got = slices.Clone(want), slices.Clone(got)
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(want, got) {
+		t.Errorf("%s = %v; want %v", what, got, want)
+	}
+}
+
+func assertSamePerson(t *testing.T, want, got models.Person) {
+	t.Helper()
+	if want.Aka == nil {
+		want.Aka = []string{}
+	}
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("person = %s; want %s", gotJSON, wantJSON)
+	}
+}
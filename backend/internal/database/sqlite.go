@@ -0,0 +1,851 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+	"modernc.org/sqlite" // also registers the "sqlite" driver
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteSchema creates the tables on first use. Relationships are stored as
+// given; SPOUSE and SIBLING rows match in either direction when queried.
+const sqliteSchema = `
+	CREATE TABLE IF NOT EXISTS persons (
+		id               TEXT PRIMARY KEY,
+		name             TEXT NOT NULL,
+		aka              TEXT NOT NULL DEFAULT '[]',
+		gender           TEXT NOT NULL DEFAULT '',
+		is_alive         INTEGER NOT NULL DEFAULT 0,
+		birth_date       TEXT NOT NULL DEFAULT '',
+		death_date       TEXT,
+		current_location TEXT NOT NULL DEFAULT '',
+		profession       TEXT NOT NULL DEFAULT '',
+		photo_url        TEXT NOT NULL DEFAULT '',
+		birth_place      TEXT NOT NULL DEFAULT '',
+		death_place      TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE TABLE IF NOT EXISTS relationships (
+		type       TEXT NOT NULL CHECK (type IN ('PARENT_CHILD', 'SPOUSE', 'SIBLING')),
+		source_id  TEXT NOT NULL REFERENCES persons(id) ON DELETE CASCADE,
+		target_id  TEXT NOT NULL REFERENCES persons(id) ON DELETE CASCADE,
+		start_date TEXT,
+		end_date   TEXT,
+		end_reason TEXT,
+		PRIMARY KEY (type, source_id, target_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS relationships_target ON relationships (target_id);
+`
+
+// sqliteColumns are columns added after the first release. Databases
+// created before them are migrated when opened.
+var sqliteColumns = []struct{ table, column, definition string }{
+	{"relationships", "end_reason", "TEXT"},
+	{"persons", "birth_place", "TEXT NOT NULL DEFAULT ''"},
+	{"persons", "death_place", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// linkColumns is the column list read by queryLinks
+const linkColumns = `type, source_id, target_id, start_date, end_date, end_reason`
+
+// personColumns is the column list read by scanPerson
+const personColumns = `p.id, p.name, p.aka, p.gender, p.is_alive, p.birth_date, p.death_date,
+	p.current_location, p.profession, p.photo_url, p.birth_place, p.death_place`
+
+// matchLink is the WHERE clause selecting one relationship by type and
+// endpoints. Arguments: type, source, target, source, target.
+const matchLink = `type = ? AND ((source_id = ? AND target_id = ?)
+	OR (type <> 'PARENT_CHILD' AND target_id = ? AND source_id = ?))`
+
+// reachableCTE collects the IDs within a number of hops of a person over
+// every relationship in either direction. Arguments: center ID, depth.
+const reachableCTE = `
+	WITH RECURSIVE
+		edges(a, b) AS (
+			SELECT source_id, target_id FROM relationships
+			UNION ALL
+			SELECT target_id, source_id FROM relationships
+		),
+		reach(id, depth) AS (
+			SELECT id, 0 FROM persons WHERE id = ?
+			UNION
+			SELECT edges.b, reach.depth + 1
+			FROM reach JOIN edges ON edges.a = reach.id
+			WHERE reach.depth < ?
+		)
+`
+
+// SQLiteRepository implements Repository interface with an embedded SQLite
+// database stored in a single file
+type SQLiteRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+// NewSQLiteRepository opens (creating if needed) the SQLite database at path
+func NewSQLiteRepository(path string, timeouts Timeouts) (*SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", classifySQLiteError(err))
+	}
+	// A single connection serializes writers and keeps the pragmas in effect
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", classifySQLiteError(err))
+	}
+	if err := migrateSQLite(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteRepository{db: db, timeouts: timeouts}, nil
+}
+
+// migrateSQLite adds the sqliteColumns missing from an existing database
+func migrateSQLite(ctx context.Context, db *sql.DB) error {
+	for _, col := range sqliteColumns {
+		var exists bool
+		err := db.QueryRowContext(ctx, `SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`, col.table, col.column).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to inspect SQLite schema: %w", classifySQLiteError(err))
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `ALTER TABLE `+col.table+` ADD COLUMN `+col.column+` `+col.definition); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", col.table, col.column, classifySQLiteError(err))
+		}
+	}
+	return nil
+}
+
+// Close closes the SQLite database
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+// GetTreeData returns nodes and links for the family tree visualization
+func (r *SQLiteRepository) GetTreeData(ctx context.Context, centerNodeID string, depth int) (*models.TreeResponse, error) {
+	if depth <= 0 {
+		depth = 2
+	}
+	if depth > 3 {
+		depth = 3
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	nodes, err := r.queryPersons(ctx, reachableCTE+`
+		SELECT `+personColumns+` FROM persons p
+		WHERE p.id IN (SELECT id FROM reach)
+		ORDER BY p.rowid
+	`, centerNodeID, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := r.queryLinks(ctx, reachableCTE+`
+		SELECT `+linkColumns+` FROM relationships
+		WHERE source_id IN (SELECT id FROM reach) AND target_id IN (SELECT id FROM reach)
+		ORDER BY rowid
+	`, centerNodeID, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TreeResponse{
+		Nodes: nodes,
+		Links: links,
+	}, nil
+}
+
+// GetPersonByID returns a person by their ID
+func (r *SQLiteRepository) GetPersonByID(ctx context.Context, id string) (*models.Person, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+
+	persons, err := r.queryPersons(ctx, `SELECT `+personColumns+` FROM persons p WHERE p.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(persons) == 0 {
+		return nil, fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+	}
+
+	return &persons[0], nil
+}
+
+// GetImmediateFamily returns the immediate family of a person
+func (r *SQLiteRepository) GetImmediateFamily(ctx context.Context, id string) (*models.ImmediateFamily, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+
+	person, err := r.GetPersonByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	family := &models.ImmediateFamily{Person: *person}
+
+	family.Parents, err = r.queryPersons(ctx, `
+		SELECT `+personColumns+` FROM relationships r JOIN persons p ON p.id = r.source_id
+		WHERE r.type = 'PARENT_CHILD' AND r.target_id = ?
+		ORDER BY r.rowid
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	family.Children, err = r.queryPersons(ctx, `
+		SELECT `+personColumns+` FROM relationships r JOIN persons p ON p.id = r.target_id
+		WHERE r.type = 'PARENT_CHILD' AND r.source_id = ?
+		ORDER BY r.rowid
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	family.Siblings, err = r.queryPersons(ctx, `
+		SELECT `+personColumns+` FROM relationships r
+		JOIN persons p ON p.id = CASE WHEN r.source_id = ? THEN r.target_id ELSE r.source_id END
+		WHERE r.type = 'SIBLING' AND (r.source_id = ? OR r.target_id = ?)
+		ORDER BY r.rowid
+	`, id, id, id)
+	if err != nil {
+		return nil, err
+	}
+
+	marriages, err := r.queryLinks(ctx, `
+		SELECT `+linkColumns+` FROM relationships
+		WHERE type = 'SPOUSE' AND (source_id = ? OR target_id = ?)
+		ORDER BY rowid
+	`, id, id)
+	if err != nil {
+		return nil, err
+	}
+	partnerIDs := make([]string, 0, len(marriages))
+	for _, link := range marriages {
+		partnerIDs = append(partnerIDs, otherEnd(link, id))
+	}
+	spouses, err := r.personsByID(ctx, partnerIDs)
+	if err != nil {
+		return nil, err
+	}
+	partners := make(map[string]models.Person, len(spouses))
+	for _, spouse := range spouses {
+		partners[spouse.ID] = spouse
+	}
+	family.Partnerships = buildPartnerships(family.Person, marriages, partners)
+
+	// The PARENT_CHILD links of every child of the person's parents and
+	// their spouses, and the SPOUSE links of the parents
+	links, err := r.queryLinks(ctx, `
+		WITH parents(id) AS (
+			SELECT source_id FROM relationships WHERE type = 'PARENT_CHILD' AND target_id = ?
+		),
+		elders(id) AS (
+			SELECT id FROM parents
+			UNION
+			SELECT CASE WHEN source_id IN (SELECT id FROM parents) THEN target_id ELSE source_id END
+			FROM relationships
+			WHERE type = 'SPOUSE' AND (source_id IN (SELECT id FROM parents) OR target_id IN (SELECT id FROM parents))
+		),
+		kids(id) AS (
+			SELECT target_id FROM relationships
+			WHERE type = 'PARENT_CHILD' AND source_id IN (SELECT id FROM elders)
+		)
+		SELECT `+linkColumns+` FROM relationships
+		WHERE (type = 'PARENT_CHILD' AND target_id IN (SELECT id FROM kids))
+		   OR (type = 'SPOUSE' AND (source_id IN (SELECT id FROM parents) OR target_id IN (SELECT id FROM parents)))
+		ORDER BY rowid
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	kin := deriveKin(id, links)
+	if family.FullSiblings, err = r.personsByID(ctx, kin.fullSiblings); err != nil {
+		return nil, err
+	}
+	if family.HalfSiblings, err = r.personsByID(ctx, kin.halfSiblings); err != nil {
+		return nil, err
+	}
+	if family.StepSiblings, err = r.personsByID(ctx, kin.stepSiblings); err != nil {
+		return nil, err
+	}
+	if family.StepParents, err = r.personsByID(ctx, kin.stepParents); err != nil {
+		return nil, err
+	}
+
+	return family, nil
+}
+
+// ExecuteQuery is not supported: raw queries are written in Cypher
+func (r *SQLiteRepository) ExecuteQuery(ctx context.Context, query string) (*models.QueryResponse, error) {
+	return nil, fmt.Errorf("raw queries: %w", ErrNotSupported)
+}
+
+// GetAllPersons returns all persons in insertion order
+func (r *SQLiteRepository) GetAllPersons(ctx context.Context) ([]models.Person, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	return r.queryPersons(ctx, `SELECT `+personColumns+` FROM persons p ORDER BY p.rowid`)
+}
+
+// GetAllRelationships returns all relationships in insertion order
+func (r *SQLiteRepository) GetAllRelationships(ctx context.Context) ([]models.Link, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	return r.queryLinks(ctx, `
+		SELECT `+linkColumns+` FROM relationships
+		ORDER BY rowid
+	`)
+}
+
+// UpsertPersons creates or updates persons by ID in a single transaction
+func (r *SQLiteRepository) UpsertPersons(ctx context.Context, persons []models.Person) ([]models.UpsertStatus, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+
+	statuses := make([]models.UpsertStatus, len(persons))
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		for i, person := range persons {
+			stored, err := queryPersonsTx(ctx, tx, `SELECT `+personColumns+` FROM persons p WHERE p.id = ?`, person.ID)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case len(stored) == 0:
+				statuses[i] = models.UpsertCreated
+			case samePerson(stored[0], person):
+				statuses[i] = models.UpsertUnchanged
+				continue
+			default:
+				statuses[i] = models.UpsertUpdated
+			}
+
+			if err := writePerson(ctx, tx, person); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert persons: %w", classifySQLiteError(err))
+	}
+
+	return statuses, nil
+}
+
+// ExistingPersonIDs reports which of the given IDs belong to stored persons
+func (r *SQLiteRepository) ExistingPersonIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	existing := make(map[string]bool)
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	query := `SELECT id FROM persons WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+		}
+		existing[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+	}
+
+	return existing, nil
+}
+
+// UpsertRelationships creates relationships or updates their dates in a
+// single transaction. PARENT_CHILD links that would make someone their own
+// ancestor are skipped.
+func (r *SQLiteRepository) UpsertRelationships(ctx context.Context, links []models.Link) ([]models.UpsertStatus, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+
+	statuses := make([]models.UpsertStatus, len(links))
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		for i, link := range links {
+			if err := checkRelationshipType(link.Relationship); err != nil {
+				return err
+			}
+			if err := checkEndpointsTx(ctx, tx, link); err != nil {
+				return err
+			}
+
+			stored, err := findLinkTx(ctx, tx, link.Relationship, link.Source, link.Target)
+			if err != nil {
+				return err
+			}
+
+			cycle := false
+			if stored == nil && link.Relationship == models.RelationshipParentChild {
+				if cycle, err = isAncestorTx(ctx, tx, link.Target, link.Source); err != nil {
+					return err
+				}
+			}
+
+			switch {
+			case cycle:
+				statuses[i] = models.UpsertFailed
+			case stored == nil:
+				statuses[i] = models.UpsertCreated
+				err = insertLink(ctx, tx, link)
+			case sameLinkDetails(*stored, link):
+				statuses[i] = models.UpsertUnchanged
+			default:
+				statuses[i] = models.UpsertUpdated
+				_, err = updateLinkDetails(ctx, tx, link)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert relationships: %w", classifySQLiteError(err))
+	}
+
+	return statuses, nil
+}
+
+// CreatePerson creates a new person row
+func (r *SQLiteRepository) CreatePerson(ctx context.Context, person models.Person) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		stored, err := queryPersonsTx(ctx, tx, `SELECT `+personColumns+` FROM persons p WHERE p.id = ?`, person.ID)
+		if err != nil {
+			return err
+		}
+		if len(stored) > 0 {
+			return fmt.Errorf("person with id %s: %w", person.ID, ErrAlreadyExists)
+		}
+		return writePerson(ctx, tx, person)
+	})
+}
+
+// UpdatePerson replaces all fields of an existing person row
+func (r *SQLiteRepository) UpdatePerson(ctx context.Context, person models.Person) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	aka, err := json.Marshal(nonNilAka(person.Aka))
+	if err != nil {
+		return fmt.Errorf("failed to encode aka: %w", classifySQLiteError(err))
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE persons SET name = ?, aka = ?, gender = ?, is_alive = ?, birth_date = ?, death_date = ?,
+		                   current_location = ?, profession = ?, photo_url = ?, birth_place = ?, death_place = ?
+		WHERE id = ?
+	`, person.Name, string(aka), person.Gender, person.IsAlive, person.BirthDate, person.DeathDate,
+		person.CurrentLocation, person.Profession, person.PhotoURL, person.BirthPlace, person.DeathPlace, person.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update person: %w", classifySQLiteError(err))
+	}
+
+	return requireAffected(result, fmt.Sprintf("person with id %s", person.ID))
+}
+
+// DeletePerson deletes a person row; their relationships cascade
+func (r *SQLiteRepository) DeletePerson(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM persons WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete person: %w", classifySQLiteError(err))
+	}
+
+	return requireAffected(result, fmt.Sprintf("person with id %s", id))
+}
+
+// CreateRelationship creates a relationship between two existing persons
+func (r *SQLiteRepository) CreateRelationship(ctx context.Context, link models.Link) error {
+	if err := checkRelationshipType(link.Relationship); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := checkEndpointsTx(ctx, tx, link); err != nil {
+			return err
+		}
+
+		stored, err := findLinkTx(ctx, tx, link.Relationship, link.Source, link.Target)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			return fmt.Errorf("%s relationship between %s and %s: %w", link.Relationship, link.Source, link.Target, ErrAlreadyExists)
+		}
+
+		if link.Relationship == models.RelationshipParentChild {
+			// The new parent must not already descend from the child
+			cycle, err := isAncestorTx(ctx, tx, link.Target, link.Source)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return fmt.Errorf("%s as parent of %s: %w", link.Source, link.Target, ErrCycle)
+			}
+		}
+
+		return insertLink(ctx, tx, link)
+	})
+}
+
+// UpdateRelationship replaces the dates of an existing relationship
+func (r *SQLiteRepository) UpdateRelationship(ctx context.Context, link models.Link) error {
+	if err := checkRelationshipType(link.Relationship); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := updateLinkDetails(ctx, tx, link)
+		if err != nil {
+			return err
+		}
+		return requireAffected(result, fmt.Sprintf("%s relationship between %s and %s", link.Relationship, link.Source, link.Target))
+	})
+}
+
+// DeleteRelationship deletes a relationship between two persons
+func (r *SQLiteRepository) DeleteRelationship(ctx context.Context, relType, source, target string) error {
+	if err := checkRelationshipType(relType); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM relationships WHERE `+matchLink,
+		relType, source, target, source, target)
+	if err != nil {
+		return fmt.Errorf("failed to delete relationship: %w", classifySQLiteError(err))
+	}
+
+	return requireAffected(result, fmt.Sprintf("%s relationship between %s and %s", relType, source, target))
+}
+
+// withTx runs fn in a transaction, committing on success
+func (r *SQLiteRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", classifySQLiteError(err))
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", classifySQLiteError(err))
+	}
+	return nil
+}
+
+// queryPersons runs a query selecting personColumns
+func (r *SQLiteRepository) queryPersons(ctx context.Context, query string, args ...any) ([]models.Person, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+	}
+	return scanPersons(rows)
+}
+
+// personsByID returns the persons with the given IDs in the same order,
+// skipping IDs that are not stored
+func (r *SQLiteRepository) personsByID(ctx context.Context, ids []string) ([]models.Person, error) {
+	if len(ids) == 0 {
+		return make([]models.Person, 0), nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	found, err := r.queryPersons(ctx, `SELECT `+personColumns+` FROM persons p WHERE p.id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.Person, len(found))
+	for _, person := range found {
+		byID[person.ID] = person
+	}
+	persons := make([]models.Person, 0, len(ids))
+	for _, id := range ids {
+		if person, ok := byID[id]; ok {
+			persons = append(persons, person)
+		}
+	}
+	return persons, nil
+}
+
+// queryLinks runs a query selecting linkColumns
+func (r *SQLiteRepository) queryLinks(ctx context.Context, query string, args ...any) ([]models.Link, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+	}
+	defer rows.Close()
+
+	links := make([]models.Link, 0)
+	for rows.Next() {
+		var link models.Link
+		var startDate, endDate, endReason sql.NullString
+		if err := rows.Scan(&link.Relationship, &link.Source, &link.Target, &startDate, &endDate, &endReason); err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+		}
+		link.StartDate = nullStringPtr(startDate)
+		link.EndDate = nullStringPtr(endDate)
+		link.EndReason = nullStringPtr(endReason)
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+	}
+
+	return links, nil
+}
+
+// queryPersonsTx runs a query selecting personColumns inside a transaction
+func queryPersonsTx(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]models.Person, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+	}
+	return scanPersons(rows)
+}
+
+// scanPersons reads and closes rows selecting personColumns
+func scanPersons(rows *sql.Rows) ([]models.Person, error) {
+	defer rows.Close()
+
+	persons := make([]models.Person, 0)
+	for rows.Next() {
+		var person models.Person
+		var aka string
+		var deathDate sql.NullString
+		if err := rows.Scan(&person.ID, &person.Name, &aka, &person.Gender, &person.IsAlive,
+			&person.BirthDate, &deathDate, &person.CurrentLocation, &person.Profession, &person.PhotoURL,
+			&person.BirthPlace, &person.DeathPlace); err != nil {
+			return nil, fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+		}
+		if err := json.Unmarshal([]byte(aka), &person.Aka); err != nil || person.Aka == nil {
+			person.Aka = []string{}
+		}
+		person.DeathDate = nullStringPtr(deathDate)
+		persons = append(persons, person)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+	}
+
+	return persons, nil
+}
+
+// writePerson inserts a person row or replaces the existing one
+func writePerson(ctx context.Context, tx *sql.Tx, person models.Person) error {
+	aka, err := json.Marshal(nonNilAka(person.Aka))
+	if err != nil {
+		return fmt.Errorf("failed to encode aka: %w", classifySQLiteError(err))
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO persons (id, name, aka, gender, is_alive, birth_date, death_date,
+		                     current_location, profession, photo_url, birth_place, death_place)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			aka = excluded.aka,
+			gender = excluded.gender,
+			is_alive = excluded.is_alive,
+			birth_date = excluded.birth_date,
+			death_date = excluded.death_date,
+			current_location = excluded.current_location,
+			profession = excluded.profession,
+			photo_url = excluded.photo_url,
+			birth_place = excluded.birth_place,
+			death_place = excluded.death_place
+	`, person.ID, person.Name, string(aka), person.Gender, person.IsAlive, person.BirthDate, person.DeathDate,
+		person.CurrentLocation, person.Profession, person.PhotoURL, person.BirthPlace, person.DeathPlace)
+	if err != nil {
+		return fmt.Errorf("failed to write person %s: %w", person.ID, err)
+	}
+	return nil
+}
+
+// checkEndpointsTx returns ErrNotFound if either person of a link is missing
+func checkEndpointsTx(ctx context.Context, tx *sql.Tx, link models.Link) error {
+	for _, id := range []string{link.Source, link.Target} {
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT count(*) FROM persons WHERE id = ?`, id).Scan(&count); err != nil {
+			return fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+		}
+		if count == 0 {
+			return fmt.Errorf("person with id %s: %w", id, ErrNotFound)
+		}
+	}
+	return nil
+}
+
+// findLinkTx returns the stored relationship matching type and endpoints, or nil
+func findLinkTx(ctx context.Context, tx *sql.Tx, relType, source, target string) (*models.Link, error) {
+	link := models.Link{Relationship: relType}
+	var startDate, endDate, endReason sql.NullString
+	err := tx.QueryRowContext(ctx, `
+		SELECT source_id, target_id, start_date, end_date, end_reason FROM relationships WHERE `+matchLink,
+		relType, source, target, source, target,
+	).Scan(&link.Source, &link.Target, &startDate, &endDate, &endReason)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+	}
+
+	link.StartDate = nullStringPtr(startDate)
+	link.EndDate = nullStringPtr(endDate)
+	link.EndReason = nullStringPtr(endReason)
+	return &link, nil
+}
+
+// isAncestorTx reports whether ancestorID can be reached from id by
+// following PARENT_CHILD links upwards
+func isAncestorTx(ctx context.Context, tx *sql.Tx, ancestorID, id string) (bool, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, `
+		WITH RECURSIVE ancestors(id) AS (
+			SELECT source_id FROM relationships WHERE type = 'PARENT_CHILD' AND target_id = ?
+			UNION
+			SELECT r.source_id FROM relationships r JOIN ancestors a ON r.target_id = a.id
+			WHERE r.type = 'PARENT_CHILD'
+		)
+		SELECT count(*) FROM ancestors WHERE id = ?
+	`, id, ancestorID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", classifySQLiteError(err))
+	}
+	return count > 0, nil
+}
+
+// insertLink inserts a relationship row
+func insertLink(ctx context.Context, tx *sql.Tx, link models.Link) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO relationships (type, source_id, target_id, start_date, end_date, end_reason)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, link.Relationship, link.Source, link.Target, link.StartDate, link.EndDate, link.EndReason)
+	if err != nil {
+		return fmt.Errorf("failed to create relationship: %w", classifySQLiteError(err))
+	}
+	return nil
+}
+
+// updateLinkDetails replaces the dates and end reason of the matching relationship
+func updateLinkDetails(ctx context.Context, tx *sql.Tx, link models.Link) (sql.Result, error) {
+	result, err := tx.ExecContext(ctx, `UPDATE relationships SET start_date = ?, end_date = ?, end_reason = ? WHERE `+matchLink,
+		link.StartDate, link.EndDate, link.EndReason, link.Relationship, link.Source, link.Target, link.Source, link.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update relationship: %w", classifySQLiteError(err))
+	}
+	return result, nil
+}
+
+// requireAffected returns ErrNotFound, described by what, if a statement
+// changed no rows
+func requireAffected(result sql.Result, what string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error processing results: %w", classifySQLiteError(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", what, ErrNotFound)
+	}
+	return nil
+}
+
+// classifySQLiteError marks timeouts and a busy, locked or unreadable
+// database with the matching sentinel error
+func classifySQLiteError(err error) error {
+	if err == nil || classified(err) {
+		return err
+	}
+
+	var sqliteErr *sqlite.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case errors.Is(err, sql.ErrConnDone):
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	case errors.As(err, &sqliteErr):
+		// Extended result codes keep the primary code in the low byte
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED, sqlite3.SQLITE_CANTOPEN, sqlite3.SQLITE_IOERR, sqlite3.SQLITE_FULL:
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+	}
+	return err
+}
+
+// nullStringPtr converts a nullable column to *string
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+// nonNilAka stores an absent aka list as an empty JSON array
+func nonNilAka(aka []string) []string {
+	if aka == nil {
+		return []string{}
+	}
+	return aka
+}
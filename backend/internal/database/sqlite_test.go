@@ -0,0 +1,126 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) database.Repository {
+		repo, err := database.NewSQLiteRepository(filepath.Join(t.TempDir(), "family_tree.db"), database.DefaultTimeouts)
+		if err != nil {
+			t.Fatalf("NewSQLiteRepository: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+func TestSQLiteRepositoryTimeout(t *testing.T) {
+	repo, err := database.NewSQLiteRepository(filepath.Join(t.TempDir(), "family_tree.db"), database.DefaultTimeouts)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	if _, err := repo.GetAllPersons(ctx); !errors.Is(err, database.ErrTimeout) {
+		t.Errorf("GetAllPersons(expired) error = %v; want ErrTimeout", err)
+	}
+	if err := repo.CreatePerson(ctx, models.Person{ID: "p1", Name: "P"}); !errors.Is(err, database.ErrTimeout) {
+		t.Errorf("CreatePerson(expired) error = %v; want ErrTimeout", err)
+	}
+}
+
+func TestSQLiteRepositoryMigration(t *testing.T) {
+	// A database created before relationships had an end_reason column
+	path := filepath.Join(t.TempDir(), "family_tree.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE relationships (
+		type       TEXT NOT NULL,
+		source_id  TEXT NOT NULL,
+		target_id  TEXT NOT NULL,
+		start_date TEXT,
+		end_date   TEXT,
+		PRIMARY KEY (type, source_id, target_id)
+	)`); err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+	db.Close()
+
+	repo, err := database.NewSQLiteRepository(path, database.DefaultTimeouts)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "b"} {
+		if err := repo.CreatePerson(ctx, models.Person{ID: id, Name: id}); err != nil {
+			t.Fatalf("CreatePerson: %v", err)
+		}
+	}
+	annulment := models.EndReasonAnnulment
+	if err := repo.CreateRelationship(ctx, models.Link{Relationship: models.RelationshipSpouse, Source: "a", Target: "b", EndReason: &annulment}); err != nil {
+		t.Fatalf("CreateRelationship: %v", err)
+	}
+	links, err := repo.GetAllRelationships(ctx)
+	if err != nil {
+		t.Fatalf("GetAllRelationships: %v", err)
+	}
+	if len(links) != 1 || links[0].EndReason == nil || *links[0].EndReason != annulment {
+		t.Errorf("relationships = %+v; want one ended by annulment", links)
+	}
+}
+
+func TestSQLiteRepositoryPersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "family_tree.db")
+	repo, err := database.NewSQLiteRepository(path, database.DefaultTimeouts)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository: %v", err)
+	}
+
+	died := "2010-08-20"
+	parent := models.Person{ID: "gp-001", Name: "Robert Smith", Aka: []string{"Bob", "Rob"}, Gender: "Male",
+		BirthDate: "1928-07-14", DeathDate: &died, Profession: "Carpenter"}
+	child := models.Person{ID: "dad-001", Name: "John Smith", Aka: []string{}, Gender: "Male", IsAlive: true, BirthDate: "1955-03-02"}
+	if _, err := repo.UpsertPersons(ctx, []models.Person{parent, child}); err != nil {
+		t.Fatalf("UpsertPersons: %v", err)
+	}
+	if _, err := repo.UpsertRelationships(ctx, []models.Link{{Relationship: models.RelationshipParentChild, Source: "gp-001", Target: "dad-001"}}); err != nil {
+		t.Fatalf("UpsertRelationships: %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	repo, err = database.NewSQLiteRepository(path, database.DefaultTimeouts)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer repo.Close()
+
+	got, err := repo.GetPersonByID(ctx, "gp-001")
+	if err != nil || !reflect.DeepEqual(*got, parent) {
+		t.Errorf("GetPersonByID after reopen = %+v, %v; want %+v", got, err, parent)
+	}
+	family, err := repo.GetImmediateFamily(ctx, "dad-001")
+	if err != nil || len(family.Parents) != 1 || family.Parents[0].ID != "gp-001" {
+		t.Errorf("GetImmediateFamily after reopen = %+v, %v; want parent gp-001", family, err)
+	}
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/config"
+)
+
+// Timeouts bounds each kind of repository operation on top of the caller's
+// context. A zero duration leaves only the caller's deadline.
+type Timeouts struct {
+	Lookup time.Duration // single person or family lookups
+	Read   time.Duration // tree traversals and full scans
+	Query  time.Duration // raw Cypher queries
+	Write  time.Duration // single person or relationship writes
+	Bulk   time.Duration // batch upserts from CSV uploads
+}
+
+// DefaultTimeouts are the limits config.Load uses when the
+// DB_*_TIMEOUT_SECONDS variables are not set
+var DefaultTimeouts = TimeoutsFromConfig(&config.Config{
+	DBLookupTimeoutSeconds: config.DefaultDBLookupTimeoutSeconds,
+	DBReadTimeoutSeconds:   config.DefaultDBReadTimeoutSeconds,
+	DBQueryTimeoutSeconds:  config.DefaultDBQueryTimeoutSeconds,
+	DBWriteTimeoutSeconds:  config.DefaultDBWriteTimeoutSeconds,
+	DBBulkTimeoutSeconds:   config.DefaultDBBulkTimeoutSeconds,
+})
+
+// TimeoutsFromConfig reads the DB_*_TIMEOUT_SECONDS settings
+func TimeoutsFromConfig(cfg *config.Config) Timeouts {
+	return Timeouts{
+		Lookup: time.Duration(cfg.DBLookupTimeoutSeconds) * time.Second,
+		Read:   time.Duration(cfg.DBReadTimeoutSeconds) * time.Second,
+		Query:  time.Duration(cfg.DBQueryTimeoutSeconds) * time.Second,
+		Write:  time.Duration(cfg.DBWriteTimeoutSeconds) * time.Second,
+		Bulk:   time.Duration(cfg.DBBulkTimeoutSeconds) * time.Second,
+	}
+}
+
+// withTimeout derives a context that is cancelled with ctx or after d
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
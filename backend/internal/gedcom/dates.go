@@ -0,0 +1,71 @@
+package gedcom
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// months are the GEDCOM month abbreviations, January first
+var months = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+// parseDate converts a GEDCOM date to YYYY-MM-DD. Missing months and days
+// become the first of the year or month, qualified dates (ABT, BEF, AFT, EST,
+// CAL, INT) drop the qualifier and ranges (BET ... AND, FROM ... TO) keep
+// their first date; all of these are reported as not exact. Dates in other
+// calendars and date phrases are not ok.
+func parseDate(value string) (iso string, exact, ok bool) {
+	fields := strings.Fields(strings.ToUpper(value))
+	if i := slices.IndexFunc(fields, func(f string) bool { return strings.HasPrefix(f, "(") }); i >= 0 {
+		fields = fields[:i]
+	}
+	if len(fields) > 0 && (fields[0] == "@#DGREGORIAN@" || fields[0] == "GREGORIAN") {
+		fields = fields[1:]
+	}
+
+	exact = true
+	if len(fields) > 0 {
+		switch fields[0] {
+		case "ABT", "CAL", "EST", "BEF", "AFT", "BET", "FROM", "TO", "INT":
+			fields, exact = fields[1:], false
+		}
+	}
+	if i := slices.IndexFunc(fields, func(f string) bool { return f == "AND" || f == "TO" }); i >= 0 {
+		fields, exact = fields[:i], false
+	}
+
+	day, month := 1, 1
+	switch len(fields) {
+	case 1:
+		exact = false
+	case 2:
+		exact = false
+		month = slices.Index(months, fields[0]) + 1
+	case 3:
+		month = slices.Index(months, fields[1]) + 1
+		var err error
+		if day, err = strconv.Atoi(fields[0]); err != nil {
+			return "", false, false
+		}
+	default:
+		return "", false, false
+	}
+
+	// A dual year such as 1731/32 is read as the later, modern year
+	yearText, dual, _ := strings.Cut(fields[len(fields)-1], "/")
+	year, err := strconv.Atoi(yearText)
+	if err != nil || month == 0 || year < 1 || year > 9998 {
+		return "", false, false
+	}
+	if dual != "" {
+		year++
+	}
+
+	iso = fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	if _, err := time.Parse("2006-01-02", iso); err != nil {
+		return "", false, false
+	}
+	return iso, exact, true
+}
@@ -0,0 +1,472 @@
+package gedcom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// PersonRecord is an individual (INDI) record mapped onto a person
+type PersonRecord struct {
+	Line   int // line number of the INDI record
+	Person models.Person
+}
+
+// LinkRecord is a relationship taken from a family (FAM) record
+type LinkRecord struct {
+	Line int // line number of the FAM record
+	Link models.Link
+}
+
+// Import is a GEDCOM file mapped onto persons and relationships. Person IDs
+// are the cross-reference IDs of the INDI records without the @ signs,
+// after the ID prefix and a dash, unless a record has a REFN of TYPE
+// IDType, as Encode writes.
+type Import struct {
+	Persons []PersonRecord
+	Links   []LinkRecord
+	// Issues are problems found while mapping, such as approximate dates or
+	// pointers to records that do not exist. Row is the line number and
+	// Column the tag path.
+	Issues []models.ValidationIssue
+	// Unmapped counts the tags that were skipped because persons and
+	// relationships have no field for them, in order of first appearance
+	Unmapped []models.UnmappedTag
+}
+
+// Read parses a GEDCOM file and maps its records. Cross-reference IDs such
+// as I1 are reused by every file, so person IDs are prefixed with prefix or,
+// if it is empty, with "ged-" and the start of the SHA-256 of the file:
+// importing the same file again updates the same persons, while files from
+// other sites or relatives do not overwrite each other.
+func Read(r io.Reader, prefix string) (*Import, error) {
+	hash := sha256.New()
+	records, err := Parse(io.TeeReader(r, hash))
+	if err != nil {
+		return nil, err
+	}
+	if prefix == "" {
+		prefix = "ged-" + hex.EncodeToString(hash.Sum(nil))[:8]
+	}
+	return Decode(records, prefix), nil
+}
+
+// PresumedDeadAge is the age in years past which an individual without a
+// DEAT event is imported as deceased
+const PresumedDeadAge = 110
+
+// Decode maps INDI records onto persons and FAM records onto SPOUSE and
+// PARENT_CHILD relationships. Individuals are taken as living unless they
+// have a DEAT event or were born more than PresumedDeadAge years ago. FAMC and FAMS links that a FAM record does not repeat
+// are added to the family. Person IDs are the cross-reference IDs after
+// prefix and a dash, or without one if prefix is empty.
+func Decode(records []*Line, prefix string) *Import {
+	if prefix != "" {
+		prefix += "-"
+	}
+	d := &decoder{
+		imp:      &Import{Issues: []models.ValidationIssue{}, Unmapped: []models.UnmappedTag{}},
+		prefix:   prefix,
+		now:      time.Now(),
+		unmapped: make(map[string]int),
+		ids:      make(map[string]string),
+		taken:    make(map[string]bool),
+		famc:     make(map[string][]string),
+		fams:     make(map[string][]string),
+		refs:     make(map[string]*Line),
+	}
+
+	var families []*Line
+	for _, record := range records {
+		switch record.Tag {
+		case "HEAD":
+			d.header(record)
+		case "TRLR":
+		case "INDI":
+			d.individual(record)
+		case "FAM":
+			families = append(families, record)
+		default:
+			d.skip(record.Tag, record)
+		}
+	}
+
+	linked := make(map[string]bool)
+	for _, record := range families {
+		d.family(record, linked)
+		delete(d.refs, record.XRef)
+	}
+
+	// Whatever is left points to a family that does not exist
+	var dangling []*Line
+	for _, line := range d.refs {
+		dangling = append(dangling, line)
+	}
+	slices.SortFunc(dangling, func(a, b *Line) int { return a.Number - b.Number })
+	for _, line := range dangling {
+		d.warn(line, "INDI."+line.Tag, line.Value, "unknown family "+line.Value+" ignored")
+	}
+
+	slices.SortStableFunc(d.imp.Unmapped, func(a, b models.UnmappedTag) int { return a.FirstLine - b.FirstLine })
+	return d.imp
+}
+
+// decoder accumulates an Import
+type decoder struct {
+	imp      *Import
+	prefix   string              // prepended to cross-reference IDs
+	now      time.Time           // date of the import, for PresumedDeadAge
+	unmapped map[string]int      // tag path -> index in imp.Unmapped
+	ids      map[string]string   // cross-reference ID -> person ID
+	taken    map[string]bool     // IDs of the persons read so far
+	famc     map[string][]string // family -> individuals listing it as FAMC
+	fams     map[string][]string // family -> individuals listing it as FAMS
+	refs     map[string]*Line    // family -> first FAMC or FAMS line naming it
+}
+
+// header checks the character set declared in HEAD
+func (d *decoder) header(record *Line) {
+	if char := record.Child("CHAR"); char != nil {
+		switch strings.ToUpper(strings.TrimSpace(char.Value)) {
+		case "UTF-8", "UNICODE", "ASCII":
+		default:
+			d.warn(char, "HEAD.CHAR", char.Value, fmt.Sprintf("character set %s is read as UTF-8", char.Value))
+		}
+	}
+}
+
+// individual maps an INDI record onto a person
+func (d *decoder) individual(record *Line) {
+	if record.XRef == "" {
+		d.fail(record, "INDI", "", "INDI record without a cross-reference ID")
+		return
+	}
+	if _, seen := d.ids[record.XRef]; seen {
+		d.fail(record, "INDI", "@"+record.XRef+"@", "duplicate cross-reference ID @"+record.XRef+"@")
+		return
+	}
+
+	person := models.Person{ID: d.prefix + record.XRef, Aka: []string{}, IsAlive: true}
+	named := false
+	for _, line := range record.Children {
+		switch line.Tag {
+		case "NAME":
+			name := d.name(line, &person)
+			switch {
+			case !named:
+				person.Name, named = name, true
+			case name != "" && name != person.Name:
+				person.Aka = appendUnique(person.Aka, name)
+			}
+		case "NICK":
+			person.Aka = nicknames(person.Aka, line.Value)
+		case "SEX":
+			person.Gender = gender(line.Value)
+			d.skipChildren("INDI.SEX", line)
+		case "BIRT":
+			person.BirthDate, person.BirthPlace = d.event("INDI.BIRT", line)
+		case "DEAT":
+			person.IsAlive = false
+			var deathDate string
+			deathDate, person.DeathPlace = d.event("INDI.DEAT", line)
+			if deathDate != "" {
+				person.DeathDate = &deathDate
+			}
+		case "OCCU":
+			if occupation := strings.TrimSpace(line.Value); occupation != "" {
+				if person.Profession != "" {
+					occupation = person.Profession + ", " + occupation
+				}
+				person.Profession = occupation
+			}
+			d.skipChildren("INDI.OCCU", line)
+		case "RESI":
+			if place := d.eventPlace("INDI.RESI", line); place != "" {
+				person.CurrentLocation = place
+			}
+		case "REFN":
+			id := strings.TrimSpace(line.Value)
+			if kind := line.Child("TYPE"); kind == nil || strings.TrimSpace(kind.Value) != IDType || id == "" {
+				d.skip("INDI.REFN", line)
+				continue
+			}
+			person.ID = id
+		case "FAMC", "FAMS":
+			if family := line.Pointer(); family != "" {
+				index := d.famc
+				if line.Tag == "FAMS" {
+					index = d.fams
+				}
+				index[family] = appendUnique(index[family], record.XRef)
+				if _, seen := d.refs[family]; !seen {
+					d.refs[family] = line
+				}
+			}
+			d.skipChildren("INDI."+line.Tag, line)
+		default:
+			d.skip("INDI."+line.Tag, line)
+		}
+	}
+
+	if d.taken[person.ID] {
+		d.fail(record, "INDI.REFN", person.ID, "duplicate person ID "+person.ID)
+		return
+	}
+	d.ids[record.XRef] = person.ID
+	d.taken[person.ID] = true
+
+	if person.Gender == "" {
+		person.Gender = "Other"
+		d.warn(record, "INDI.SEX", "", "no SEX given; imported as Other")
+	}
+	// Dates that could not be read have been warned about already
+	if birth := record.Child("BIRT"); birth == nil || birth.Child("DATE") == nil {
+		d.warn(record, "INDI.BIRT.DATE", "", "no birth date given; imported without one")
+	}
+	if record.Child("DEAT") == nil {
+		d.presumeLiving(record, &person)
+	}
+	d.imp.Persons = append(d.imp.Persons, PersonRecord{Line: record.Number, Person: person})
+}
+
+// presumeLiving decides whether person, who has no DEAT event, is alive.
+// GEDCOM files often leave out the deaths of distant ancestors, so anyone
+// born more than PresumedDeadAge years ago is taken to be dead.
+func (d *decoder) presumeLiving(record *Line, person *models.Person) {
+	birth, err := time.Parse("2006-01-02", person.BirthDate)
+	if err != nil {
+		d.warn(record, "INDI.DEAT", "", "no DEAT given and the birth date is unknown; imported as living")
+		return
+	}
+	if birth.AddDate(PresumedDeadAge, 0, 0).Before(d.now) {
+		person.IsAlive = false
+		d.warn(record, "INDI.DEAT", "", fmt.Sprintf("no DEAT given, but born more than %d years ago; imported as deceased", PresumedDeadAge))
+	}
+}
+
+// name formats a NAME line and adds its nicknames to person.Aka
+func (d *decoder) name(line *Line, person *models.Person) string {
+	var given, surname string
+	for _, part := range line.Children {
+		switch part.Tag {
+		case "NICK":
+			person.Aka = nicknames(person.Aka, part.Value)
+		case "GIVN":
+			given = part.Value
+		case "SURN":
+			surname = part.Value
+		case "NPFX", "SPFX", "NSFX", "TYPE":
+			// Parts of the name value itself
+		default:
+			d.skip("INDI.NAME."+part.Tag, part)
+		}
+	}
+
+	name := strings.Join(strings.Fields(strings.ReplaceAll(line.Value, "/", " ")), " ")
+	if name == "" {
+		name = strings.Join(strings.Fields(given+" "+surname), " ")
+	}
+	return name
+}
+
+// nicknames adds the comma-separated names of a NICK value to aka
+func nicknames(aka []string, value string) []string {
+	for _, nick := range strings.Split(value, ",") {
+		aka = appendUnique(aka, strings.TrimSpace(nick))
+	}
+	return aka
+}
+
+// gender maps a SEX value onto models.Person.Gender
+func gender(sex string) string {
+	switch strings.ToUpper(strings.TrimSpace(sex)) {
+	case "M":
+		return "Male"
+	case "F":
+		return "Female"
+	}
+	return "Other"
+}
+
+// event reads the DATE and PLAC of an event such as BIRT
+func (d *decoder) event(path string, line *Line) (date, place string) {
+	for _, detail := range line.Children {
+		switch detail.Tag {
+		case "DATE":
+			date = d.date(path+".DATE", detail)
+		case "PLAC":
+			place = strings.TrimSpace(detail.Value)
+			d.skipChildren(path+".PLAC", detail)
+		default:
+			d.skip(path+"."+detail.Tag, detail)
+		}
+	}
+	return date, place
+}
+
+// eventDate reads the DATE of an event whose place has nowhere to go
+func (d *decoder) eventDate(path string, line *Line) string {
+	var date string
+	for _, detail := range line.Children {
+		if detail.Tag == "DATE" {
+			date = d.date(path+".DATE", detail)
+		} else {
+			d.skip(path+"."+detail.Tag, detail)
+		}
+	}
+	return date
+}
+
+// eventPlace reads the PLAC of an event whose date has nowhere to go
+func (d *decoder) eventPlace(path string, line *Line) string {
+	var place string
+	for _, detail := range line.Children {
+		if detail.Tag == "PLAC" {
+			place = strings.TrimSpace(detail.Value)
+			d.skipChildren(path+".PLAC", detail)
+		} else {
+			d.skip(path+"."+detail.Tag, detail)
+		}
+	}
+	return place
+}
+
+// date converts a DATE line to YYYY-MM-DD. Partial and approximate dates
+// are imported with a warning; dates that name no calendar day are left
+// empty.
+func (d *decoder) date(path string, line *Line) string {
+	iso, exact, ok := parseDate(line.Value)
+	switch {
+	case !ok:
+		d.warn(line, path, line.Value, fmt.Sprintf("date %q is not a Gregorian calendar date; left empty", line.Value))
+		return ""
+	case !exact:
+		d.warn(line, path, line.Value, fmt.Sprintf("approximate date %q imported as %s", line.Value, iso))
+	}
+	return iso
+}
+
+// family turns a FAM record into a SPOUSE link between the partners and a
+// PARENT_CHILD link from each partner to each child. linked holds the keys
+// of the links added so far, so that a family described twice adds nothing.
+func (d *decoder) family(record *Line, linked map[string]bool) {
+	var partners, children []string
+	var marriage models.Link
+	for _, line := range record.Children {
+		switch line.Tag {
+		case "HUSB", "WIFE", "CHIL":
+			xref := line.Pointer()
+			if xref == "" {
+				continue
+			}
+			id, known := d.ids[xref]
+			if !known {
+				d.fail(line, "FAM."+line.Tag, line.Value, "unknown individual "+line.Value)
+				continue
+			}
+			if line.Tag == "CHIL" {
+				children = appendUnique(children, id)
+			} else {
+				partners = appendUnique(partners, id)
+			}
+			d.skipChildren("FAM."+line.Tag, line)
+		case "MARR":
+			if date := d.eventDate("FAM.MARR", line); date != "" {
+				marriage.StartDate = &date
+			}
+		case "DIV", "ANUL":
+			reason := models.EndReasonDivorce
+			if line.Tag == "ANUL" {
+				reason = models.EndReasonAnnulment
+			}
+			marriage.EndReason = &reason
+			if date := d.eventDate("FAM."+line.Tag, line); date != "" {
+				marriage.EndDate = &date
+			}
+		default:
+			d.skip("FAM."+line.Tag, line)
+		}
+	}
+	for _, xref := range d.fams[record.XRef] {
+		partners = appendUnique(partners, d.ids[xref])
+	}
+	for _, xref := range d.famc[record.XRef] {
+		children = appendUnique(children, d.ids[xref])
+	}
+
+	add := func(link models.Link) {
+		key := link.Relationship + ":" + link.Source + ":" + link.Target
+		reverse := link.Relationship + ":" + link.Target + ":" + link.Source
+		if linked[key] || (link.Relationship == models.RelationshipSpouse && linked[reverse]) {
+			return
+		}
+		linked[key] = true
+		d.imp.Links = append(d.imp.Links, LinkRecord{Line: record.Number, Link: link})
+	}
+
+	if len(partners) > 2 {
+		d.warn(record, "FAM", "@"+record.XRef+"@", "family has more than two partners; only the first two are linked as spouses")
+	}
+	if len(partners) >= 2 {
+		marriage.Relationship = models.RelationshipSpouse
+		marriage.Source, marriage.Target = partners[0], partners[1]
+		add(marriage)
+	}
+	for _, parent := range partners {
+		for _, child := range children {
+			add(models.Link{Relationship: models.RelationshipParentChild, Source: parent, Target: child})
+		}
+	}
+}
+
+// skip records line as unmapped under path. Its subordinate lines are not
+// reported separately.
+func (d *decoder) skip(path string, line *Line) {
+	if i, seen := d.unmapped[path]; seen {
+		d.imp.Unmapped[i].Count++
+		return
+	}
+	d.unmapped[path] = len(d.imp.Unmapped)
+	d.imp.Unmapped = append(d.imp.Unmapped, models.UnmappedTag{Tag: path, Count: 1, FirstLine: line.Number})
+}
+
+// skipChildren records the subordinate lines of a mapped line as unmapped
+func (d *decoder) skipChildren(path string, line *Line) {
+	for _, child := range line.Children {
+		d.skip(path+"."+child.Tag, child)
+	}
+}
+
+// warn adds a warning about line
+func (d *decoder) warn(line *Line, path, value, message string) {
+	d.issue(line, path, value, models.SeverityWarning, message)
+}
+
+// fail adds an error about line
+func (d *decoder) fail(line *Line, path, value, message string) {
+	d.issue(line, path, value, models.SeverityError, message)
+}
+
+func (d *decoder) issue(line *Line, path, value, severity, message string) {
+	d.imp.Issues = append(d.imp.Issues, models.ValidationIssue{
+		Row:      line.Number,
+		Column:   path,
+		Value:    value,
+		Severity: severity,
+		Message:  message,
+	})
+}
+
+// appendUnique appends s unless it is empty or already present
+func appendUnique(list []string, s string) []string {
+	if s == "" || slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
@@ -0,0 +1,391 @@
+package gedcom
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Version is a GEDCOM version that Encode can write
+type Version string
+
+// Supported GEDCOM versions
+const (
+	Version551 Version = "5.5.1"
+	Version70  Version = "7.0"
+)
+
+// Versions lists every version Encode can write
+var Versions = []Version{Version551, Version70}
+
+// ParseVersion returns the version for a name such as "7.0"
+func ParseVersion(name string) (Version, bool) {
+	version := Version(name)
+	return version, slices.Contains(Versions, version)
+}
+
+// IDType is the REFN TYPE under which Encode records each person's ID.
+// Cross-reference IDs are limited to a few characters, so Encode numbers
+// the records and Read restores the IDs from REFN.
+const IDType = "FAMILY_TREE_ID"
+
+// maxValueLength is where GEDCOM 5.5.1 values are split into CONC lines,
+// keeping each line well under the 255 characters the standard allows
+const maxValueLength = 200
+
+// Encode writes persons and the SPOUSE and PARENT_CHILD links between them
+// as a GEDCOM file that Read maps back onto the same persons and links.
+// Each couple becomes a FAM record with their children; a parent whose
+// co-parent is not their spouse gets a FAM record of their own. SIBLING
+// links, photos and the end of marriages ended by death are not written;
+// GEDCOM implies siblings from shared families and deaths from DEAT.
+func Encode(w io.Writer, persons []models.Person, links []models.Link, version Version) error {
+	persons = slices.Clone(persons)
+	slices.SortFunc(persons, func(a, b models.Person) int { return cmp.Compare(a.ID, b.ID) })
+
+	e := &encoder{
+		w:       bufio.NewWriter(w),
+		version: version,
+		xrefs:   make(map[string]string, len(persons)),
+		persons: make(map[string]models.Person, len(persons)),
+	}
+	for i, p := range persons {
+		e.xrefs[p.ID] = fmt.Sprintf("I%d", i+1)
+		e.persons[p.ID] = p
+	}
+	families := e.families(links)
+
+	e.header()
+	for _, p := range persons {
+		e.individual(p, families)
+	}
+	for _, f := range families {
+		e.family(f)
+	}
+	e.line(0, "", "TRLR", "")
+	return e.w.Flush()
+}
+
+// family is a FAM record to write. Partners holds one or two person IDs;
+// Marriage is the SPOUSE link between two partners.
+type family struct {
+	xref     string
+	partners []string
+	children []string
+	marriage *models.Link
+}
+
+// encoder writes GEDCOM lines. Write errors are kept by the bufio.Writer
+// and returned by Flush.
+type encoder struct {
+	w       *bufio.Writer
+	version Version
+	xrefs   map[string]string // person ID -> cross-reference ID
+	persons map[string]models.Person
+}
+
+// families groups the links between known persons into FAM records
+func (e *encoder) families(links []models.Link) []*family {
+	links = slices.Clone(links)
+	slices.SortFunc(links, func(a, b models.Link) int {
+		return cmp.Or(cmp.Compare(a.Relationship, b.Relationship), cmp.Compare(a.Source, b.Source), cmp.Compare(a.Target, b.Target))
+	})
+
+	var families []*family
+	couples := make(map[[2]string]*family)
+	singles := make(map[string]*family)
+	parents := make(map[string][]string)
+	for _, link := range links {
+		_, sourceOK := e.persons[link.Source]
+		_, targetOK := e.persons[link.Target]
+		if !sourceOK || !targetOK || link.Source == link.Target {
+			continue
+		}
+
+		switch link.Relationship {
+		case models.RelationshipSpouse:
+			key := coupleKey(link.Source, link.Target)
+			if couples[key] == nil {
+				f := &family{partners: e.partnerOrder(link.Source, link.Target), marriage: &link}
+				couples[key] = f
+				families = append(families, f)
+			}
+		case models.RelationshipParentChild:
+			parents[link.Target] = appendUnique(parents[link.Target], link.Source)
+		}
+	}
+
+	children := make([]string, 0, len(parents))
+	for child := range parents {
+		children = append(children, child)
+	}
+	slices.SortFunc(children, e.byBirth)
+
+	for _, child := range children {
+		ps := parents[child]
+		placed := make([]bool, len(ps))
+		for i := range ps {
+			for j := i + 1; j < len(ps); j++ {
+				if f := couples[coupleKey(ps[i], ps[j])]; f != nil && !placed[i] && !placed[j] {
+					f.children = append(f.children, child)
+					placed[i], placed[j] = true, true
+				}
+			}
+		}
+		for i, parent := range ps {
+			if placed[i] {
+				continue
+			}
+			f := singles[parent]
+			if f == nil {
+				f = &family{partners: []string{parent}}
+				singles[parent] = f
+				families = append(families, f)
+			}
+			f.children = append(f.children, child)
+		}
+	}
+
+	for i, f := range families {
+		f.xref = fmt.Sprintf("F%d", i+1)
+	}
+	return families
+}
+
+// coupleKey identifies a couple regardless of the order of the partners
+func coupleKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// partnerOrder puts a husband before a wife; otherwise it keeps the order
+func (e *encoder) partnerOrder(a, b string) []string {
+	if e.persons[a].Gender == "Female" && e.persons[b].Gender == "Male" {
+		return []string{b, a}
+	}
+	return []string{a, b}
+}
+
+// byBirth orders person IDs by birth date, then ID
+func (e *encoder) byBirth(a, b string) int {
+	return cmp.Or(cmp.Compare(e.persons[a].BirthDate, e.persons[b].BirthDate), cmp.Compare(a, b))
+}
+
+// header writes HEAD and, for 5.5.1, the submitter record it requires
+func (e *encoder) header() {
+	e.line(0, "", "HEAD", "")
+	if e.version == Version70 {
+		e.line(1, "", "GEDC", "")
+		e.line(2, "", "VERS", "7.0")
+		e.line(1, "", "SOUR", "FAMILY_TREE")
+		e.line(2, "", "NAME", "Family Tree")
+		return
+	}
+
+	e.line(1, "", "SOUR", "FAMILY_TREE")
+	e.line(2, "", "NAME", "Family Tree")
+	e.pointer(1, "SUBM", "SUBM")
+	e.line(1, "", "GEDC", "")
+	e.line(2, "", "VERS", "5.5.1")
+	e.line(2, "", "FORM", "LINEAGE-LINKED")
+	e.line(1, "", "CHAR", "UTF-8")
+	e.line(0, "SUBM", "SUBM", "")
+	e.line(1, "", "NAME", "Family Tree")
+}
+
+// individual writes the INDI record of a person
+func (e *encoder) individual(p models.Person, families []*family) {
+	e.line(0, e.xrefs[p.ID], "INDI", "")
+	e.line(1, "", "NAME", p.Name)
+	if len(p.Aka) > 0 {
+		// 5.5.1 allows one NICK per name, with names separated by commas
+		if e.version == Version551 {
+			e.line(2, "", "NICK", strings.Join(p.Aka, ", "))
+		} else {
+			for _, aka := range p.Aka {
+				e.line(2, "", "NICK", aka)
+			}
+		}
+	}
+	e.line(1, "", "SEX", e.sex(p.Gender))
+
+	e.event("BIRT", formatDate(p.BirthDate), p.BirthPlace)
+	if !p.IsAlive {
+		var deathDate string
+		if p.DeathDate != nil {
+			deathDate = formatDate(*p.DeathDate)
+		}
+		e.event("DEAT", deathDate, p.DeathPlace)
+	}
+	if p.Profession != "" {
+		e.line(1, "", "OCCU", p.Profession)
+	}
+	if p.CurrentLocation != "" {
+		e.line(1, "", "RESI", "")
+		e.line(2, "", "PLAC", p.CurrentLocation)
+	}
+	e.line(1, "", "REFN", p.ID)
+	e.line(2, "", "TYPE", IDType)
+
+	for _, f := range families {
+		if slices.Contains(f.children, p.ID) {
+			e.pointer(1, "FAMC", f.xref)
+		}
+	}
+	for _, f := range families {
+		if slices.Contains(f.partners, p.ID) {
+			e.pointer(1, "FAMS", f.xref)
+		}
+	}
+}
+
+// sex maps models.Person.Gender onto a SEX value
+func (e *encoder) sex(gender string) string {
+	switch gender {
+	case "Male":
+		return "M"
+	case "Female":
+		return "F"
+	case "Other":
+		if e.version == Version70 {
+			return "X"
+		}
+	}
+	return "U"
+}
+
+// family writes a FAM record
+func (e *encoder) family(f *family) {
+	e.line(0, f.xref, "FAM", "")
+	for i, partner := range f.partners {
+		tag := "HUSB"
+		if i == 1 || (len(f.partners) == 1 && e.persons[partner].Gender == "Female") {
+			tag = "WIFE"
+		}
+		e.pointer(1, tag, e.xrefs[partner])
+	}
+	for _, child := range f.children {
+		e.pointer(1, "CHIL", e.xrefs[child])
+	}
+
+	if m := f.marriage; m != nil {
+		var date string
+		if m.StartDate != nil {
+			date = formatDate(*m.StartDate)
+		}
+		e.event("MARR", date, "")
+
+		if m.EndReason != nil && *m.EndReason != models.EndReasonDeath {
+			tag := "DIV"
+			if *m.EndReason == models.EndReasonAnnulment {
+				tag = "ANUL"
+			}
+			date = ""
+			if m.EndDate != nil {
+				date = formatDate(*m.EndDate)
+			}
+			e.event(tag, date, "")
+		}
+	}
+}
+
+// event writes an event with its date and place. An event without either
+// is written as "Y" to say that it happened, where the version allows it.
+func (e *encoder) event(tag, date, place string) {
+	if date == "" && place == "" {
+		value := "Y"
+		if e.version == Version551 && tag != "BIRT" && tag != "DEAT" && tag != "MARR" {
+			value = ""
+		}
+		e.line(1, "", tag, value)
+		return
+	}
+	e.line(1, "", tag, "")
+	if date != "" {
+		e.line(2, "", "DATE", date)
+	}
+	if place != "" {
+		e.line(2, "", "PLAC", place)
+	}
+}
+
+// pointer writes a line whose value points to the record xref
+func (e *encoder) pointer(level int, tag, xref string) {
+	e.write(level, "", tag, "@"+xref+"@")
+}
+
+// line writes one text line, continuing multi-line values with CONT and,
+// for 5.5.1, long values with CONC
+func (e *encoder) line(level int, xref, tag, value string) {
+	for i, text := range strings.Split(value, "\n") {
+		if i > 0 {
+			xref, tag = "", "CONT"
+		}
+		text = e.escape(text)
+		head, tail := text, ""
+		if e.version == Version551 {
+			head, tail = splitValue(text)
+		}
+		e.write(level+min(i, 1), xref, tag, head)
+		for tail != "" {
+			head, tail = splitValue(tail)
+			e.write(level+1, "", "CONC", head)
+		}
+	}
+}
+
+func (e *encoder) write(level int, xref, tag, value string) {
+	fmt.Fprintf(e.w, "%d ", level)
+	if xref != "" {
+		fmt.Fprintf(e.w, "@%s@ ", xref)
+	}
+	e.w.WriteString(tag)
+	if value != "" {
+		e.w.WriteString(" " + value)
+	}
+	e.w.WriteString("\r\n")
+}
+
+// escape doubles @ signs in text: all of them for 5.5.1, a leading one for 7.0
+func (e *encoder) escape(value string) string {
+	if e.version == Version551 {
+		return strings.ReplaceAll(value, "@", "@@")
+	}
+	if strings.HasPrefix(value, "@") {
+		return "@" + value
+	}
+	return value
+}
+
+// splitValue cuts value before maxValueLength bytes, at a rune boundary
+// between two characters that are neither spaces, which readers may trim
+// around CONC, nor @ signs, which may be escaped in pairs
+func splitValue(value string) (head, tail string) {
+	if len(value) <= maxValueLength {
+		return value, ""
+	}
+	for i := maxValueLength; i > 1; i-- {
+		if utf8.RuneStart(value[i]) && !strings.ContainsAny(value[i-1:i+1], " @") {
+			return value[:i], value[i:]
+		}
+	}
+	return value, ""
+}
+
+// formatDate converts YYYY-MM-DD to a GEDCOM date such as "12 MAR 1920".
+// Anything else is left empty.
+func formatDate(iso string) string {
+	var year, month, day int
+	if _, err := fmt.Sscanf(iso, "%04d-%02d-%02d", &year, &month, &day); err != nil || month < 1 || month > 12 {
+		return ""
+	}
+	return fmt.Sprintf("%d %s %d", day, months[month-1], year)
+}
@@ -0,0 +1,144 @@
+// Package gedcom reads and writes GEDCOM, the file format genealogy programs
+// and sites use to exchange family trees, and maps its records onto persons
+// and relationships. Files are read and written as UTF-8; both GEDCOM 5.5.1
+// and 7.0 are supported.
+package gedcom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Line is one GEDCOM line with its subordinate lines. CONC and CONT lines
+// have already been folded into Value.
+type Line struct {
+	Number   int    // 1-based line number in the file
+	Level    int    // 0 for records
+	XRef     string // cross-reference ID without the @ signs, if any
+	Tag      string
+	Value    string
+	Children []*Line
+}
+
+// Child returns the first subordinate line with the given tag, or nil
+func (l *Line) Child(tag string) *Line {
+	for _, child := range l.Children {
+		if child.Tag == tag {
+			return child
+		}
+	}
+	return nil
+}
+
+// Pointer returns the cross-reference ID the value points to, without the
+// @ signs, or "" if the value is not a pointer
+func (l *Line) Pointer() string {
+	value := strings.TrimSpace(l.Value)
+	if len(value) > 2 && value[0] == '@' && value[len(value)-1] == '@' && value != "@VOID@" {
+		return value[1 : len(value)-1]
+	}
+	return ""
+}
+
+// ParseError reports a line that is not valid GEDCOM
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// maxLineLength bounds a single line; the standard allows far less
+const maxLineLength = 1 << 20
+
+// Parse reads a GEDCOM file into its level-0 records. Blank lines are
+// skipped; any other malformed line is a *ParseError.
+func Parse(r io.Reader) ([]*Line, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	var records []*Line
+	var open []*Line // open[i] is the last line read at level i
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		line, err := parseLine(number, text)
+		if err != nil {
+			return nil, err
+		}
+		if line.Level > len(open) {
+			return nil, &ParseError{number, fmt.Sprintf("level %d follows level %d", line.Level, len(open)-1)}
+		}
+		open = open[:line.Level]
+
+		switch line.Tag {
+		case "CONT", "CONC":
+			if line.Level == 0 {
+				return nil, &ParseError{number, line.Tag + " cannot start a record"}
+			}
+			parent := open[line.Level-1]
+			if line.Tag == "CONT" {
+				parent.Value += "\n"
+			}
+			parent.Value += line.Value
+			continue
+		}
+
+		if line.Level == 0 {
+			records = append(records, line)
+		} else {
+			parent := open[line.Level-1]
+			parent.Children = append(parent.Children, line)
+		}
+		open = append(open, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read GEDCOM: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, &ParseError{number, "no records found"}
+	}
+	return records, nil
+}
+
+// parseLine splits "level [@xref@] tag [value]"
+func parseLine(number int, text string) (*Line, error) {
+	rest := strings.TrimLeft(text, " \t")
+	levelText, rest, _ := strings.Cut(rest, " ")
+	level, err := strconv.Atoi(levelText)
+	if err != nil || level < 0 || level > 99 {
+		return nil, &ParseError{number, fmt.Sprintf("invalid level %q", levelText)}
+	}
+
+	line := &Line{Number: number, Level: level}
+	if strings.HasPrefix(rest, "@") {
+		var xref string
+		xref, rest, _ = strings.Cut(rest, " ")
+		if len(xref) < 3 || !strings.HasSuffix(xref, "@") {
+			return nil, &ParseError{number, fmt.Sprintf("invalid cross-reference ID %q", xref)}
+		}
+		line.XRef = xref[1 : len(xref)-1]
+	}
+
+	line.Tag, line.Value, _ = strings.Cut(rest, " ")
+	if line.Tag == "" {
+		return nil, &ParseError{number, "missing tag"}
+	}
+	// Text values double their @ signs: GEDCOM 5.5.1 all of them, 7.0 a
+	// leading one
+	line.Value = strings.ReplaceAll(line.Value, "@@", "@")
+	return line, nil
+}
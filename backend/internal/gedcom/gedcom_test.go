@@ -0,0 +1,304 @@
+package gedcom
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestRead(t *testing.T) {
+	file, err := os.Open("testdata/family.ged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	imported, err := Read(file, "fam")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	var persons []string
+	for _, record := range imported.Persons {
+		p := record.Person
+		death := "-"
+		if p.DeathDate != nil {
+			death = *p.DeathDate
+		}
+		persons = append(persons, fmt.Sprintf("%d %s|%s|%v|%s|%t|%s|%s|%s|%s|%s|%s",
+			record.Line, p.ID, p.Name, p.Aka, p.Gender, p.IsAlive, p.BirthDate, p.BirthPlace, death, p.DeathPlace, p.Profession, p.CurrentLocation))
+	}
+	wantPersons := []string{
+		"9 fam-I1|Ram Sharma|[Ramu]|Male|false|1920-03-12|Jaipur, Rajasthan, India|1990-01-01|Delhi, India|Teacher|Delhi, India",
+		"26 fam-I2|Sita Verma|[Sita Sharma]|Female|false|1925-01-01||-|||",
+		"35 fam-I3|Mohan Sharma|[]|Male|true|1950-06-05||-||Engineer|",
+		"45 fam-I4|Geeta|[]|Other|true|1952-01-01||-|||",
+		"60 fam-I5|Kamla Sharma|[]|Female|true|||-|||",
+		"66 fam-I6|Hari Sharma|[]|Male|false|||-|||",
+		"70 fam-I7|Bhola Sharma|[]|Male|false|1850-07-04||-|||",
+	}
+	if strings.Join(persons, "\n") != strings.Join(wantPersons, "\n") {
+		t.Errorf("persons =\n%s\nwant\n%s", strings.Join(persons, "\n"), strings.Join(wantPersons, "\n"))
+	}
+
+	var links []string
+	for _, record := range imported.Links {
+		l := record.Link
+		s := fmt.Sprintf("%d %s:%s:%s", record.Line, l.Relationship, l.Source, l.Target)
+		for _, field := range []*string{l.StartDate, l.EndDate, l.EndReason} {
+			if field != nil {
+				s += " " + *field
+			}
+		}
+		links = append(links, s)
+	}
+	wantLinks := []string{
+		"51 SPOUSE:fam-I1:fam-I2 1948-02-01 1960-04-03 divorce",
+		"51 PARENT_CHILD:fam-I1:fam-I3",
+		"51 PARENT_CHILD:fam-I1:fam-I4",
+		"51 PARENT_CHILD:fam-I1:fam-I5",
+		"51 PARENT_CHILD:fam-I2:fam-I3",
+		"51 PARENT_CHILD:fam-I2:fam-I4",
+		"51 PARENT_CHILD:fam-I2:fam-I5",
+	}
+	if fmt.Sprint(links) != fmt.Sprint(wantLinks) {
+		t.Errorf("links = %q; want %q", links, wantLinks)
+	}
+
+	var issues []string
+	for _, issue := range imported.Issues {
+		issues = append(issues, fmt.Sprintf("%d %s %s: %s", issue.Row, issue.Severity, issue.Column, issue.Message))
+	}
+	wantIssues := []string{
+		`20 warning INDI.DEAT.DATE: approximate date "ABT 1990" imported as 1990-01-01`,
+		`32 warning INDI.BIRT.DATE: approximate date "1925" imported as 1925-01-01`,
+		`48 warning INDI.BIRT.DATE: approximate date "BET 1952 AND 1954" imported as 1952-01-01`,
+		`45 warning INDI.SEX: no SEX given; imported as Other`,
+		`64 warning INDI.BIRT.DATE: date "(during the monsoon)" is not a Gregorian calendar date; left empty`,
+		`60 warning INDI.DEAT: no DEAT given and the birth date is unknown; imported as living`,
+		`66 warning INDI.BIRT.DATE: no birth date given; imported without one`,
+		`70 warning INDI.DEAT: no DEAT given, but born more than 110 years ago; imported as deceased`,
+		`50 warning INDI.FAMS: unknown family @F9@ ignored`,
+	}
+	if strings.Join(issues, "\n") != strings.Join(wantIssues, "\n") {
+		t.Errorf("issues =\n%s\nwant\n%s", strings.Join(issues, "\n"), strings.Join(wantIssues, "\n"))
+	}
+
+	var unmapped []string
+	for _, tag := range imported.Unmapped {
+		unmapped = append(unmapped, fmt.Sprintf("%s x%d @%d", tag.Tag, tag.Count, tag.FirstLine))
+	}
+	wantUnmapped := []string{"SUBM x1 @7", "INDI.BIRT.SOUR x1 @18", "INDI.NOTE x1 @41", "FAM.MARR.PLAC x1 @57", "SOUR x1 @75"}
+	if fmt.Sprint(unmapped) != fmt.Sprint(wantUnmapped) {
+		t.Errorf("unmapped = %q; want %q", unmapped, wantUnmapped)
+	}
+}
+
+func TestReadPrefix(t *testing.T) {
+	ids := func(content, prefix string) []string {
+		imported, err := Read(strings.NewReader(content), prefix)
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		var ids []string
+		for _, record := range imported.Persons {
+			ids = append(ids, record.Person.ID)
+		}
+		return ids
+	}
+
+	file := "0 HEAD\n0 @I1@ INDI\n1 NAME Ram\n0 @I2@ INDI\n1 REFN me-001\n2 TYPE " + IDType + "\n0 TRLR\n"
+	other := strings.Replace(file, "Ram", "Sita", 1)
+
+	first := ids(file, "")
+	if !strings.HasPrefix(first[0], "ged-") || len(first[0]) != len("ged-12345678-I1") || first[1] != "me-001" {
+		t.Errorf("IDs = %q; want a prefix derived from the file, and the REFN ID", first)
+	}
+	if again := ids(file, ""); !slices.Equal(again, first) {
+		t.Errorf("IDs on reimport = %q; want %q", again, first)
+	}
+	if second := ids(other, ""); second[0] == first[0] {
+		t.Errorf("another file got the same ID %s", second[0])
+	}
+	if got := ids(file, "smith"); !slices.Equal(got, []string{"smith-I1", "me-001"}) {
+		t.Errorf("IDs with prefix smith = %q", got)
+	}
+}
+
+func TestParseContinuation(t *testing.T) {
+	records, err := Parse(strings.NewReader("\ufeff0 @N1@ NOTE First\r\n1 CONC  part\r\n1 CONT Second line\r\n0 TRLR\r\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := records[0].Value; got != "First part\nSecond line" {
+		t.Errorf("value = %q; want continued text", got)
+	}
+	if records[0].XRef != "N1" || records[1].Tag != "TRLR" {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"0 HEAD\n2 CHAR UTF-8\n", 2},
+		{"x HEAD\n", 1},
+		{"0 HEAD\n1\n", 2},
+		{"0 @I1 INDI\n", 1},
+		{"0 CONT text\n", 1},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.input))
+		parseErr, ok := err.(*ParseError)
+		if !ok || parseErr.Line != tt.line {
+			t.Errorf("Parse(%q) error = %v; want a ParseError on line %d", tt.input, err, tt.line)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // "" when not ok
+		exact bool
+	}{
+		{"12 MAR 1920", "1920-03-12", true},
+		{"@#DGREGORIAN@ 1 jan 1900", "1900-01-01", true},
+		{"MAR 1920", "1920-03-01", false},
+		{"1920", "1920-01-01", false},
+		{"ABT 1920", "1920-01-01", false},
+		{"BEF 2 FEB 1920", "1920-02-02", false},
+		{"FROM 1920 TO 1925", "1920-01-01", false},
+		{"INT 5 MAY 1930 (from the family bible)", "1930-05-05", false},
+		{"11 FEB 1731/32", "1732-02-11", true},
+		{"31 FEB 1920", "", false},
+		{"@#DJULIAN@ 1 JAN 1700", "", false},
+		{"(before the war)", "", false},
+		{"44 B.C.", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, exact, ok := parseDate(tt.value)
+		if ok != (tt.want != "") || got != tt.want || (ok && exact != tt.exact) {
+			t.Errorf("parseDate(%q) = %q, %t, %t; want %q, %t", tt.value, got, exact, ok, tt.want, tt.exact)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	fx := repotest.LoadFixtures(t)
+	persons := append(slices.Clone(fx.Persons),
+		models.Person{
+			ID: "ex-001", Name: "Mary @ Home Jones", Aka: []string{"Molly"}, Gender: "Other",
+			BirthDate: "1960-02-29", BirthPlace: "Leeds, UK", DeathPlace: "York, UK",
+			Profession: strings.Repeat("Teacher and writer ", 15) + "@ large",
+		},
+		models.Person{ID: "kid-001", Name: "Sam Jones", Aka: []string{}, Gender: "Male", IsAlive: true, BirthDate: "1990-07-04"},
+	)
+	start, end, reason := "1981-01-01", "1984-12-31", models.EndReasonDivorce
+	links := append(slices.Clone(fx.Links),
+		models.Link{Relationship: models.RelationshipSpouse, Source: "ex-001", Target: "uncle-003", StartDate: &start, EndDate: &end, EndReason: &reason},
+		// Co-parents who are not spouses
+		models.Link{Relationship: models.RelationshipParentChild, Source: "ex-001", Target: "kid-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "uncle-004", Target: "kid-001"},
+	)
+
+	wantPersons := personStrings(persons)
+	wantLinks := linkStrings(links)
+
+	for _, version := range Versions {
+		t.Run(string(version), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, persons, links, version); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			// 7.0 has no line length limit
+			for i, line := range strings.Split(buf.String(), "\r\n") {
+				if version == Version551 && len(line) > 255 {
+					t.Errorf("line %d is %d characters long", i+1, len(line))
+				}
+			}
+
+			imported, err := Read(&buf, "")
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			// The submitter record 5.5.1 requires has nowhere to go
+			var unmapped []string
+			for _, tag := range imported.Unmapped {
+				unmapped = append(unmapped, tag.Tag)
+			}
+			if len(imported.Issues) > 0 || (len(unmapped) > 0 && fmt.Sprint(unmapped) != "[SUBM]") {
+				t.Errorf("issues = %+v, unmapped = %q; want none", imported.Issues, unmapped)
+			}
+
+			var gotPersons []models.Person
+			for _, record := range imported.Persons {
+				gotPersons = append(gotPersons, record.Person)
+			}
+			var gotLinks []models.Link
+			for _, record := range imported.Links {
+				gotLinks = append(gotLinks, record.Link)
+			}
+			if got := personStrings(gotPersons); !slices.Equal(got, wantPersons) {
+				t.Errorf("persons =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(wantPersons, "\n"))
+			}
+			if got := linkStrings(gotLinks); !slices.Equal(got, wantLinks) {
+				t.Errorf("links =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(wantLinks, "\n"))
+			}
+		})
+	}
+}
+
+// personStrings formats persons, sorted by ID, for comparison. Photos are
+// not exported.
+func personStrings(persons []models.Person) []string {
+	var out []string
+	for _, p := range persons {
+		death := "-"
+		if p.DeathDate != nil {
+			death = *p.DeathDate
+		}
+		out = append(out, fmt.Sprintf("%s|%s|%q|%s|%t|%s|%s|%s|%s|%s|%s",
+			p.ID, p.Name, p.Aka, p.Gender, p.IsAlive, p.BirthDate, p.BirthPlace, death, p.DeathPlace, p.Profession, p.CurrentLocation))
+	}
+	slices.Sort(out)
+	return out
+}
+
+// linkStrings formats SPOUSE and PARENT_CHILD links, sorted, for
+// comparison. Spouses are put in ID order, and marriages ended by death
+// lose their end, as GEDCOM records deaths on the individuals.
+func linkStrings(links []models.Link) []string {
+	var out []string
+	for _, l := range links {
+		source, target := l.Source, l.Target
+		switch l.Relationship {
+		case models.RelationshipSpouse:
+			source, target = min(source, target), max(source, target)
+		case models.RelationshipSibling:
+			continue
+		}
+		s := l.Relationship + ":" + source + ":" + target
+		if l.EndReason != nil && *l.EndReason == models.EndReasonDeath {
+			l.EndDate, l.EndReason = nil, nil
+		}
+		for _, field := range []*string{l.StartDate, l.EndDate, l.EndReason} {
+			if field != nil {
+				s += " " + *field
+			}
+		}
+		out = append(out, s)
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,99 @@
+package genealogy
+
+import "math"
+
+// Coefficients computes Wright's coefficients over the PARENT_CHILD links
+// of a graph, caching results between calls. It is not safe for concurrent
+// use.
+type Coefficients struct {
+	g       *Graph
+	depth   map[string]int
+	kinship map[[2]string]float64
+}
+
+// NewCoefficients prepares coefficient computations over g
+func NewCoefficients(g *Graph) *Coefficients {
+	return &Coefficients{
+		g:       g,
+		depth:   make(map[string]int),
+		kinship: make(map[[2]string]float64),
+	}
+}
+
+// Relationship returns Wright's coefficient of relationship between a and
+// b: the expected fraction of genes they share by descent, 0.5 for parent
+// and child or full siblings and 0.125 for first cousins
+func (c *Coefficients) Relationship(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	scale := math.Sqrt((1 + c.Inbreeding(a)) * (1 + c.Inbreeding(b)))
+	return 2 * c.Kinship(a, b) / scale
+}
+
+// Inbreeding returns the inbreeding coefficient of a person: the kinship
+// coefficient of their parents, 0 unless both parents are known
+func (c *Coefficients) Inbreeding(id string) float64 {
+	parents := c.parents(id)
+	if len(parents) < 2 {
+		return 0
+	}
+	return c.Kinship(parents[0], parents[1])
+}
+
+// Kinship returns the kinship coefficient of a and b: the probability that
+// alleles drawn at random from each are identical by descent
+func (c *Coefficients) Kinship(a, b string) float64 {
+	if a == b {
+		return (1 + c.Inbreeding(a)) / 2
+	}
+
+	key := [2]string{min(a, b), max(a, b)}
+	if k, ok := c.kinship[key]; ok {
+		return k
+	}
+
+	// Expand the person who cannot be an ancestor of the other. An
+	// ancestor is always shallower than its descendants.
+	if c.generationDepth(a) < c.generationDepth(b) {
+		a, b = b, a
+	}
+	k := 0.0
+	for _, parent := range c.parents(a) {
+		k += c.Kinship(parent, b) / 2
+	}
+
+	c.kinship[key] = k
+	return k
+}
+
+// parents returns at most two parents of id that are shallower than id,
+// which drops links closing a PARENT_CHILD cycle
+func (c *Coefficients) parents(id string) []string {
+	depth := c.generationDepth(id)
+	var parents []string
+	for _, p := range c.g.parents[id] {
+		if len(parents) < 2 && c.generationDepth(p) < depth {
+			parents = append(parents, p)
+		}
+	}
+	return parents
+}
+
+// generationDepth returns the length of the longest line of known
+// ancestors above id. A parent reached again while its own depth is being
+// computed closes a cycle and is ignored.
+func (c *Coefficients) generationDepth(id string) int {
+	if d, ok := c.depth[id]; ok {
+		return d
+	}
+	c.depth[id] = -1 // in progress
+	d := 0
+	for _, p := range c.g.parents[id] {
+		if pd := c.generationDepth(p); pd >= 0 {
+			d = max(d, pd+1)
+		}
+	}
+	c.depth[id] = d
+	return d
+}
@@ -0,0 +1,82 @@
+package genealogy_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestCoefficients(t *testing.T) {
+	extra := []models.Person{
+		{ID: "half-001", Name: "Half Sibling", Gender: "Female"},
+		{ID: "step-001", Name: "Step Mother", Gender: "Female"},
+		// kid-001 is a child of first cousins; kid-002 is their second child
+		{ID: "kid-001", Name: "Kid", Gender: "Female"},
+		{ID: "kid-002", Name: "Kid Brother", Gender: "Male"},
+	}
+	g := fixtureGraph(t, extra,
+		models.Link{Relationship: models.RelationshipParentChild, Source: "dad-001", Target: "half-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "step-001", Target: "half-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "me-001", Target: "kid-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "cousin-002", Target: "kid-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "me-001", Target: "kid-002"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "cousin-002", Target: "kid-002"},
+	)
+	c := genealogy.NewCoefficients(g)
+
+	relationship := []struct {
+		a, b string
+		want float64
+	}{
+		{"me-001", "me-001", 1},
+		{"me-001", "dad-001", 0.5},
+		{"me-001", "sibling-001", 0.5},
+		{"me-001", "half-001", 0.25},
+		{"me-001", "gp-001", 0.25},
+		{"me-001", "uncle-001", 0.25},
+		{"me-001", "cousin-001", 0.125},
+		{"me-001", "child-003", 0.0625},
+		{"child-001", "child-003", 0.03125},
+		{"me-001", "spouse-001", 0},
+		{"me-001", "step-001", 0},
+		// Kinship with an inbred child gains half the kinship of its parents
+		{"me-001", "kid-001", 2 * (0.25 + 0.0625/2) / math.Sqrt(1.0625)},
+		{"kid-001", "kid-002", 2 * (0.25 + 0.0625/2) / 1.0625},
+	}
+	for _, tt := range relationship {
+		if got := c.Relationship(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Relationship(%s, %s) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	inbreeding := []struct {
+		id   string
+		want float64
+	}{
+		{"me-001", 0},
+		{"gp-001", 0},
+		{"kid-001", 0.0625},
+	}
+	for _, tt := range inbreeding {
+		if got := c.Inbreeding(tt.id); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Inbreeding(%s) = %v; want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestCoefficientsCycle(t *testing.T) {
+	// A PARENT_CHILD cycle from bad data must not loop forever
+	g := genealogy.NewGraph(
+		[]models.Person{{ID: "a"}, {ID: "b"}},
+		[]models.Link{
+			{Relationship: models.RelationshipParentChild, Source: "a", Target: "b"},
+			{Relationship: models.RelationshipParentChild, Source: "b", Target: "a"},
+		},
+	)
+	c := genealogy.NewCoefficients(g)
+	if got := c.Relationship("a", "b"); got != 0.5 {
+		t.Errorf("Relationship(a, b) = %v; want 0.5", got)
+	}
+}
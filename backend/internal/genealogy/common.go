@@ -0,0 +1,87 @@
+package genealogy
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SharedAncestor is an ancestor of every person in a CommonAncestors query
+type SharedAncestor struct {
+	ID string
+	// Distances[i] is the number of generations from the ith person up to
+	// the ancestor, following the shortest line if there are several
+	Distances []int
+	// MRCA is set when none of the ancestor's descendants is also shared
+	MRCA bool
+}
+
+// CommonAncestors returns the ancestors shared by all the given persons,
+// following PARENT_CHILD links only. A person counts as their own ancestor
+// at distance 0, so a parent and child share the parent. Results are
+// ordered by total distance, then ID.
+func CommonAncestors(g *Graph, ids []string) []SharedAncestor {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	distances := make([]map[string]int, len(ids))
+	for i, id := range ids {
+		distances[i] = g.ancestorDistances(id)
+	}
+
+	shared := make(map[string]bool)
+	var common []SharedAncestor
+	for id := range distances[0] {
+		a := SharedAncestor{ID: id}
+		for _, d := range distances {
+			n, ok := d[id]
+			if !ok {
+				break
+			}
+			a.Distances = append(a.Distances, n)
+		}
+		if len(a.Distances) == len(ids) {
+			shared[id] = true
+			common = append(common, a)
+		}
+	}
+
+	// Every person between a shared ancestor and a shared descendant is
+	// shared too, so checking children is enough
+	for i := range common {
+		common[i].MRCA = !slices.ContainsFunc(g.children[common[i].ID], func(c string) bool { return shared[c] })
+	}
+
+	slices.SortFunc(common, func(a, b SharedAncestor) int {
+		return cmp.Or(cmp.Compare(sum(a.Distances), sum(b.Distances)), cmp.Compare(a.ID, b.ID))
+	})
+	return common
+}
+
+// ancestorDistances maps id and each of its ancestors to the fewest
+// generations between them
+func (g *Graph) ancestorDistances(id string) map[string]int {
+	distances := map[string]int{id: 0}
+	frontier := []string{id}
+	for generation := 1; len(frontier) > 0; generation++ {
+		var next []string
+		for _, child := range frontier {
+			for _, parent := range g.parents[child] {
+				if _, seen := distances[parent]; !seen {
+					distances[parent] = generation
+					next = append(next, parent)
+				}
+			}
+		}
+		frontier = next
+	}
+	return distances
+}
+
+func sum(ns []int) int {
+	total := 0
+	for _, n := range ns {
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,38 @@
+package genealogy_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+)
+
+func TestCommonAncestors(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	tests := []struct {
+		ids  []string
+		want []string // "id distances" with a trailing * for MRCAs, in order
+	}{
+		{[]string{"me-001", "cousin-001"}, []string{"gm-001 [2 2]*", "gp-001 [2 2]*", "ggm-001 [3 3]", "ggp-001 [3 3]"}},
+		{[]string{"me-001", "cousin-001", "cousin-003"}, []string{"gm-001 [2 2 2]*", "gp-001 [2 2 2]*", "ggm-001 [3 3 3]", "ggp-001 [3 3 3]"}},
+		{[]string{"child-001", "cousin-001"}, []string{"gm-001 [3 2]*", "gp-001 [3 2]*", "ggm-001 [4 3]", "ggp-001 [4 3]"}},
+		{[]string{"child-001", "child-004"}, []string{"gm-002 [3 3]*", "gp-002 [3 3]*", "ggm-002 [4 4]", "ggp-002 [4 4]"}},
+		{[]string{"dad-001", "me-001"}, []string{"dad-001 [0 1]*", "gm-001 [1 2]", "gp-001 [1 2]", "ggm-001 [2 3]", "ggp-001 [2 3]"}},
+		{[]string{"me-001", "spouse-001"}, nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, a := range genealogy.CommonAncestors(g, tt.ids) {
+			s := fmt.Sprintf("%s %v", a.ID, a.Distances)
+			if a.MRCA {
+				s += "*"
+			}
+			got = append(got, s)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("CommonAncestors(%v) = %v; want %v", tt.ids, got, tt.want)
+		}
+	}
+}
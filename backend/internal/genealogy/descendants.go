@@ -0,0 +1,126 @@
+package genealogy
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Numbering is a system for numbering descendants
+type Numbering string
+
+// Supported descendant numberings
+const (
+	// DAboville separates each child's birth order with dots: 1.2.3
+	DAboville Numbering = "daboville"
+	// Henry appends one character per generation: 123, with X for the
+	// tenth child, A-Z from the eleventh and (n) beyond that
+	Henry Numbering = "henry"
+)
+
+// Numberings lists every supported descendant numbering
+var Numberings = []Numbering{DAboville, Henry}
+
+// ParseNumbering returns the numbering for a name such as "daboville"
+func ParseNumbering(name string) (Numbering, bool) {
+	n := Numbering(name)
+	return n, slices.Contains(Numberings, n)
+}
+
+// Descent is the descendants of one person
+type Descent struct {
+	Root        *models.DescendantNode
+	Depth       int // deepest generation found
+	Descendants int // distinct descendants
+}
+
+// Descendants walks PARENT_CHILD links down from id for at most
+// generations generations. Children are ordered by birth date, those
+// without one last. It returns false if id is not in the graph.
+func Descendants(g *Graph, id string, generations int, numbering Numbering) (*Descent, bool) {
+	subject, ok := g.persons[id]
+	if !ok {
+		return nil, false
+	}
+
+	root := g.descendantNode(subject, "1", 0)
+	descent := &Descent{Root: root}
+	first := map[string]string{id: root.Number}
+
+	queue := []*models.DescendantNode{root}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		if parent.Generation == generations {
+			continue
+		}
+
+		for i, child := range g.childrenByBirth(parent.Person.ID) {
+			node := g.descendantNode(child, childNumber(numbering, parent.Number, i+1), parent.Generation+1)
+			parent.Children = append(parent.Children, node)
+			descent.Depth = max(descent.Depth, node.Generation)
+
+			if repeat, seen := first[child.ID]; seen {
+				node.RepeatOf = repeat
+				continue
+			}
+			first[child.ID] = node.Number
+			descent.Descendants++
+			queue = append(queue, node)
+		}
+	}
+	return descent, true
+}
+
+// descendantNode creates the node for a descendant and their spouses
+func (g *Graph) descendantNode(p models.Person, number string, generation int) *models.DescendantNode {
+	node := &models.DescendantNode{
+		Number:     number,
+		Generation: generation,
+		Person:     p,
+		Spouses:    []models.Person{},
+		Children:   []*models.DescendantNode{},
+	}
+	for _, id := range g.spouses[p.ID] {
+		node.Spouses = append(node.Spouses, g.persons[id])
+	}
+	return node
+}
+
+// childrenByBirth returns the children of id ordered by birth date, then ID
+func (g *Graph) childrenByBirth(id string) []models.Person {
+	children := make([]models.Person, 0, len(g.children[id]))
+	for _, c := range g.children[id] {
+		children = append(children, g.persons[c])
+	}
+	slices.SortStableFunc(children, func(a, b models.Person) int {
+		switch {
+		case a.BirthDate == b.BirthDate:
+			return cmp.Compare(a.ID, b.ID)
+		case a.BirthDate == "":
+			return 1
+		case b.BirthDate == "":
+			return -1
+		}
+		return cmp.Compare(a.BirthDate, b.BirthDate)
+	})
+	return children
+}
+
+// childNumber numbers the nth child of the descendant numbered parent
+func childNumber(numbering Numbering, parent string, n int) string {
+	if numbering != Henry {
+		return parent + "." + strconv.Itoa(n)
+	}
+	switch {
+	case n <= 9:
+		return parent + strconv.Itoa(n)
+	case n == 10:
+		return parent + "X"
+	case n <= 36:
+		return parent + string(rune('A'+n-11))
+	}
+	return parent + "(" + strconv.Itoa(n) + ")"
+}
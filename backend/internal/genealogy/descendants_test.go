@@ -0,0 +1,110 @@
+package genealogy_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// numbered flattens a descendant tree into number -> person ID, with
+// repeats recorded as "=<first number>"
+func numbered(node *models.DescendantNode, numbers map[string]string) {
+	numbers[node.Number] = node.Person.ID
+	if node.RepeatOf != "" {
+		numbers[node.Number] = "=" + node.RepeatOf
+	}
+	for _, child := range node.Children {
+		numbered(child, numbers)
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	tests := []struct {
+		numbering genealogy.Numbering
+		want      map[string]string
+	}{
+		{genealogy.DAboville, map[string]string{
+			"1": "gp-001", "1.1": "dad-001", "1.2": "uncle-001", "1.3": "aunt-002",
+			"1.1.1": "me-001", "1.1.2": "sibling-001", "1.2.1": "cousin-001",
+			"1.1.1.1": "child-001", "1.1.1.2": "child-002", "1.2.1.1": "child-003",
+		}},
+		{genealogy.Henry, map[string]string{
+			"1": "gp-001", "11": "dad-001", "12": "uncle-001", "13": "aunt-002",
+			"111": "me-001", "112": "sibling-001", "121": "cousin-001",
+			"1111": "child-001", "1112": "child-002", "1211": "child-003",
+		}},
+	}
+
+	for _, tt := range tests {
+		descent, ok := genealogy.Descendants(g, "gp-001", 3, tt.numbering)
+		if !ok {
+			t.Fatal("gp-001 not found")
+		}
+		got := make(map[string]string)
+		numbered(descent.Root, got)
+		for n, id := range tt.want {
+			if got[n] != id {
+				t.Errorf("%s %s = %q; want %q", tt.numbering, n, got[n], id)
+			}
+		}
+		if descent.Depth != 3 {
+			t.Errorf("%s depth = %d; want 3", tt.numbering, descent.Depth)
+		}
+	}
+}
+
+func TestDescendantsSpousesAndLimit(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	descent, _ := genealogy.Descendants(g, "gp-001", 1, genealogy.DAboville)
+	if descent.Depth != 1 || descent.Descendants != 3 {
+		t.Errorf("depth %d, descendants %d; want 1, 3", descent.Depth, descent.Descendants)
+	}
+	if spouses := descent.Root.Spouses; len(spouses) != 1 || spouses[0].ID != "gm-001" {
+		t.Errorf("spouses of gp-001 = %v; want gm-001", spouses)
+	}
+	for _, child := range descent.Root.Children {
+		if len(child.Children) != 0 {
+			t.Errorf("%s has children listed beyond the generation limit", child.Person.ID)
+		}
+	}
+}
+
+func TestDescendantsRepeat(t *testing.T) {
+	// A child of first cousins descends from gp-001 through both parents
+	g := fixtureGraph(t,
+		[]models.Person{{ID: "kid-001", Name: "Kid", Gender: "Female", BirthDate: "2020-01-01"}},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "me-001", Target: "kid-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "cousin-002", Target: "kid-001"},
+	)
+
+	descent, _ := genealogy.Descendants(g, "gp-001", 4, genealogy.DAboville)
+	got := make(map[string]string)
+	numbered(descent.Root, got)
+	if got["1.1.1.3"] != "kid-001" || got["1.2.2.1"] != "=1.1.1.3" {
+		t.Errorf("kid-001 numbered %q and %q; want 1.1.1.3 repeated at 1.2.2.1", got["1.1.1.3"], got["1.2.2.1"])
+	}
+}
+
+func TestHenryNumbering(t *testing.T) {
+	children := make([]models.Person, 0, 40)
+	links := make([]models.Link, 0, 40)
+	for i := 1; i <= 40; i++ {
+		id := fmt.Sprintf("many-%02d", i)
+		children = append(children, models.Person{ID: id, Name: id, BirthDate: fmt.Sprintf("2000-01-%02d", min(i, 28))})
+		links = append(links, models.Link{Relationship: models.RelationshipParentChild, Source: "loner-001", Target: id})
+	}
+	g := fixtureGraph(t, append(children, models.Person{ID: "loner-001", Name: "Parent"}), links...)
+
+	descent, _ := genealogy.Descendants(g, "loner-001", 1, genealogy.Henry)
+	want := map[int]string{1: "11", 9: "19", 10: "1X", 11: "1A", 36: "1Z", 37: "1(37)"}
+	for i, number := range want {
+		if got := descent.Root.Children[i-1].Number; got != number {
+			t.Errorf("child %d = %q; want %q", i, got, number)
+		}
+	}
+}
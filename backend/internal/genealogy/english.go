@@ -0,0 +1,200 @@
+package genealogy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// EnglishLabel names what r.To is to r.From in English genealogical terms,
+// such as "second cousin once removed", "great-aunt" or "step-mother"
+func EnglishLabel(g *Graph, r *Relationship) string {
+	if r.From == r.To {
+		return "self"
+	}
+	if r.Chain {
+		return englishChain(g, r.Hops)
+	}
+
+	gender := g.gender(r.To)
+	up, down := r.Up, r.Down
+
+	switch {
+	case r.LeadingSpouse && r.TrailingSpouse:
+		if up == 1 && down == 1 {
+			return gendered(gender, "brother-in-law", "sister-in-law", "sibling-in-law")
+		}
+		return englishChain(g, r.Hops)
+
+	case r.LeadingSpouse:
+		switch {
+		case up == 0 && down == 0:
+			return gendered(gender, "husband", "wife", "spouse")
+		case down == 0:
+			return englishBlood(up, 0, gender, false) + "-in-law"
+		case up == 0:
+			return "step-" + englishBlood(0, down, gender, false)
+		case up == 1 && down == 1:
+			return gendered(gender, "brother-in-law", "sister-in-law", "sibling-in-law")
+		}
+		spouse := gendered(g.gender(r.Hops[0].To), "husband", "wife", "spouse")
+		return spouse + "'s " + englishBlood(up, down, gender, r.Half)
+
+	case r.TrailingSpouse:
+		switch {
+		case up == 0 && down == 0:
+			return gendered(gender, "husband", "wife", "spouse")
+		case down == 0:
+			return "step-" + englishBlood(up, 0, gender, false)
+		case up == 0, up == 1:
+			return englishBlood(up, down, gender, false) + "-in-law"
+		case down == 1:
+			return englishBlood(up, down, gender, false) + " by marriage"
+		}
+		relative := r.Hops[len(r.Hops)-2].To
+		spouse := gendered(gender, "husband", "wife", "spouse")
+		return englishBlood(up, down, g.gender(relative), r.Half) + "'s " + spouse
+	}
+
+	return englishBlood(up, down, gender, r.Half)
+}
+
+// englishBlood names a blood relative up generations above the common
+// ancestor's line and down below it
+func englishBlood(up, down int, gender string, half bool) string {
+	switch {
+	case down == 0:
+		return greats(up-2) + grand(up, gendered(gender, "father", "mother", "parent"))
+	case up == 0:
+		return greats(down-2) + grand(down, gendered(gender, "son", "daughter", "child"))
+	case up == 1 && down == 1:
+		sibling := gendered(gender, "brother", "sister", "sibling")
+		if half {
+			return "half-" + sibling
+		}
+		return sibling
+	case down == 1:
+		return greats(up-2) + gendered(gender, "uncle", "aunt", "aunt/uncle")
+	case up == 1:
+		return greats(down-3) + grandNibling(down, gendered(gender, "nephew", "niece", "niece/nephew"))
+	}
+
+	degree := min(up, down) - 1
+	label := ordinalWord(degree) + " cousin"
+	if removed := abs(up - down); removed > 0 {
+		label += " " + timesRemoved(removed)
+	}
+	return label
+}
+
+// englishChain names a path that fits no pattern by chaining each hop, such
+// as "father's wife's brother"
+func englishChain(g *Graph, hops []models.Hop) string {
+	words := make([]string, len(hops))
+	for i, hop := range hops {
+		gender := g.gender(hop.To)
+		switch hop.Kind {
+		case models.HopParent:
+			words[i] = gendered(gender, "father", "mother", "parent")
+		case models.HopChild:
+			words[i] = gendered(gender, "son", "daughter", "child")
+		case models.HopSpouse:
+			words[i] = gendered(gender, "husband", "wife", "spouse")
+		case models.HopSibling:
+			words[i] = gendered(gender, "brother", "sister", "sibling")
+		}
+	}
+
+	// A parent's spouse's child is a step-sibling
+	if len(hops) == 3 && hops[0].Kind == models.HopParent && hops[1].Kind == models.HopSpouse && hops[2].Kind == models.HopChild {
+		return "step-" + gendered(g.gender(hops[2].To), "brother", "sister", "sibling")
+	}
+	return strings.Join(words, "'s ")
+}
+
+// gendered picks the word for a models.Person.Gender
+func gendered(gender, male, female, other string) string {
+	switch gender {
+	case "Male":
+		return male
+	case "Female":
+		return female
+	}
+	return other
+}
+
+// greats returns the "great-" prefix repeated n times, or as an ordinal
+// ("3rd great-") beyond two
+func greats(n int) string {
+	switch {
+	case n <= 0:
+		return ""
+	case n <= 2:
+		return strings.Repeat("great-", n)
+	}
+	return ordinal(n) + " great-"
+}
+
+// grand prefixes "grand" to a parent or child word two or more generations away
+func grand(generations int, word string) string {
+	if generations >= 2 {
+		return "grand" + word
+	}
+	return word
+}
+
+// grandNibling prefixes "grand" to a niece or nephew word three or more
+// generations below the common ancestor
+func grandNibling(down int, word string) string {
+	if down >= 3 {
+		return "grand" + word
+	}
+	return word
+}
+
+var ordinalWords = []string{"", "first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth"}
+
+// ordinalWord spells out small ordinals ("second") and abbreviates the rest
+func ordinalWord(n int) string {
+	if n < len(ordinalWords) {
+		return ordinalWords[n]
+	}
+	return ordinal(n)
+}
+
+// ordinal abbreviates an ordinal number ("3rd", "11th")
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// timesRemoved spells out how many generations apart two cousins are
+func timesRemoved(n int) string {
+	switch n {
+	case 1:
+		return "once removed"
+	case 2:
+		return "twice removed"
+	case 3:
+		return "thrice removed"
+	}
+	return fmt.Sprintf("%d times removed", n)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
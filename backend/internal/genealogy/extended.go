@@ -0,0 +1,145 @@
+package genealogy
+
+import (
+	"slices"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Relative is a member of a person's extended family. Via is the relative
+// they are connected through, such as the parent a grandparent belongs to,
+// and Relationship is the connection to pass to Describe.
+type Relative struct {
+	ID           string
+	Via          string
+	Relationship *Relationship
+}
+
+// ExtendedFamily groups the relatives one ring beyond the immediate family.
+// A relative is listed once per group, through the first connection found;
+// the person and their parents, spouses, children and siblings are left out.
+type ExtendedFamily struct {
+	Grandparents  []Relative
+	Grandchildren []Relative
+	// AuntsUncles includes the spouses of the parents' siblings
+	AuntsUncles   []Relative
+	FirstCousins  []Relative
+	NiecesNephews []Relative
+	ParentsInLaw  []Relative
+	// SiblingsInLaw holds the spouses' siblings, the siblings' spouses and
+	// the spouses of the spouses' siblings
+	SiblingsInLaw []Relative
+	ChildrenInLaw []Relative
+}
+
+// Extended collects the extended family of id. Siblings are persons with a
+// SIBLING link or a parent in common, as for the immediate family.
+func Extended(g *Graph, id string) ExtendedFamily {
+	x := &extender{g: g, id: id, close: map[string]bool{id: true}}
+	for _, ids := range [][]string{g.parents[id], g.children[id], g.spouses[id]} {
+		for _, other := range ids {
+			x.close[other] = true
+		}
+	}
+	for _, route := range g.siblingRoutes(id) {
+		x.close[end(route)] = true
+	}
+
+	var f ExtendedFamily
+	for _, parent := range g.parents[id] {
+		up := []models.Hop{{Kind: models.HopParent, To: parent}}
+		for _, gp := range g.parents[parent] {
+			x.add(&f.Grandparents, parent, pattern{}, up, models.Hop{Kind: models.HopParent, To: gp})
+		}
+		for _, route := range g.siblingRoutes(parent) {
+			relative := end(route)
+			x.add(&f.AuntsUncles, parent, pattern{}, up, route...)
+			for _, spouse := range g.spouses[relative] {
+				x.add(&f.AuntsUncles, relative, pattern{trailingSpouse: true}, slices.Concat(up, route), models.Hop{Kind: models.HopSpouse, To: spouse})
+			}
+			for _, cousin := range g.children[relative] {
+				x.add(&f.FirstCousins, relative, pattern{}, slices.Concat(up, route), models.Hop{Kind: models.HopChild, To: cousin})
+			}
+		}
+	}
+
+	for _, child := range g.children[id] {
+		down := []models.Hop{{Kind: models.HopChild, To: child}}
+		for _, grandchild := range g.children[child] {
+			x.add(&f.Grandchildren, child, pattern{}, down, models.Hop{Kind: models.HopChild, To: grandchild})
+		}
+		for _, spouse := range g.spouses[child] {
+			x.add(&f.ChildrenInLaw, child, pattern{trailingSpouse: true}, down, models.Hop{Kind: models.HopSpouse, To: spouse})
+		}
+	}
+
+	for _, route := range g.siblingRoutes(id) {
+		sibling := end(route)
+		for _, child := range g.children[sibling] {
+			x.add(&f.NiecesNephews, sibling, pattern{}, route, models.Hop{Kind: models.HopChild, To: child})
+		}
+		for _, spouse := range g.spouses[sibling] {
+			x.add(&f.SiblingsInLaw, sibling, pattern{trailingSpouse: true}, route, models.Hop{Kind: models.HopSpouse, To: spouse})
+		}
+	}
+
+	for _, spouse := range g.spouses[id] {
+		across := []models.Hop{{Kind: models.HopSpouse, To: spouse}}
+		for _, parent := range g.parents[spouse] {
+			x.add(&f.ParentsInLaw, spouse, pattern{leadingSpouse: true}, across, models.Hop{Kind: models.HopParent, To: parent})
+		}
+		for _, route := range g.siblingRoutes(spouse) {
+			relative := end(route)
+			x.add(&f.SiblingsInLaw, spouse, pattern{leadingSpouse: true}, across, route...)
+			for _, other := range g.spouses[relative] {
+				x.add(&f.SiblingsInLaw, relative, pattern{leadingSpouse: true, trailingSpouse: true}, slices.Concat(across, route), models.Hop{Kind: models.HopSpouse, To: other})
+			}
+		}
+	}
+	return f
+}
+
+// extender adds relatives to the groups of an ExtendedFamily
+type extender struct {
+	g     *Graph
+	id    string
+	close map[string]bool // the person and their immediate family
+}
+
+// add appends the relative at the end of prefix+hops to group, unless they
+// are close family or already in the group
+func (x *extender) add(group *[]Relative, via string, p pattern, prefix []models.Hop, hops ...models.Hop) {
+	path := slices.Concat(prefix, hops)
+	to := end(path)
+	if x.close[to] || slices.ContainsFunc(*group, func(r Relative) bool { return r.ID == to }) {
+		return
+	}
+	*group = append(*group, Relative{ID: to, Via: via, Relationship: x.g.newRelationship(x.id, to, path, p)})
+}
+
+// siblingRoutes returns one route from id to each sibling: the SIBLING hop
+// if there is one, otherwise up to a shared parent and down again
+func (g *Graph) siblingRoutes(id string) [][]models.Hop {
+	var routes [][]models.Hop
+	seen := map[string]bool{id: true}
+	for _, sibling := range g.siblings[id] {
+		if !seen[sibling] {
+			seen[sibling] = true
+			routes = append(routes, []models.Hop{{Kind: models.HopSibling, To: sibling}})
+		}
+	}
+	for _, parent := range g.parents[id] {
+		for _, sibling := range g.children[parent] {
+			if !seen[sibling] {
+				seen[sibling] = true
+				routes = append(routes, []models.Hop{{Kind: models.HopParent, To: parent}, {Kind: models.HopChild, To: sibling}})
+			}
+		}
+	}
+	return routes
+}
+
+// end returns the person a route arrives at
+func end(hops []models.Hop) string {
+	return hops[len(hops)-1].To
+}
@@ -0,0 +1,82 @@
+package genealogy_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestExtended(t *testing.T) {
+	extra := []models.Person{
+		{ID: "fil-001", Name: "Father In Law", Gender: "Male", BirthDate: "1955-01-01"},
+		{ID: "mil-001", Name: "Mother In Law", Gender: "Female", BirthDate: "1957-01-01"},
+		{ID: "sil-001", Name: "Spouse's Sister", Gender: "Female", BirthDate: "1990-01-01"},
+		{ID: "sil-002", Name: "Spouse's Sister's Husband", Gender: "Male", BirthDate: "1989-01-01"},
+		{ID: "bil-001", Name: "Sister's Husband", Gender: "Male", BirthDate: "1986-01-01"},
+		{ID: "cil-001", Name: "Son In Law", Gender: "Male", BirthDate: "2013-01-01"},
+		{ID: "niece-001", Name: "Niece", Gender: "Female", BirthDate: "2015-01-01"},
+	}
+	g := fixtureGraph(t, extra,
+		models.Link{Relationship: models.RelationshipParentChild, Source: "fil-001", Target: "spouse-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "mil-001", Target: "spouse-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "fil-001", Target: "sil-001"},
+		models.Link{Relationship: models.RelationshipSpouse, Source: "sil-001", Target: "sil-002"},
+		models.Link{Relationship: models.RelationshipSpouse, Source: "sibling-001", Target: "bil-001"},
+		models.Link{Relationship: models.RelationshipSpouse, Source: "child-001", Target: "cil-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "sibling-002", Target: "niece-001"},
+	)
+
+	f := genealogy.Extended(g, "me-001")
+	tests := []struct {
+		group string
+		got   []genealogy.Relative
+		want  []string // "id via label", in order
+	}{
+		{"grandparents", f.Grandparents, []string{
+			"gm-001 dad-001 grandmother", "gp-001 dad-001 grandfather", "gm-002 mom-001 grandmother", "gp-002 mom-001 grandfather",
+		}},
+		{"grandchildren", f.Grandchildren, nil},
+		{"aunts and uncles", f.AuntsUncles, []string{
+			"aunt-002 dad-001 aunt", "uncle-002 aunt-002 uncle by marriage", "uncle-001 dad-001 uncle", "aunt-001 uncle-001 aunt by marriage",
+			"aunt-004 mom-001 aunt", "uncle-004 aunt-004 uncle by marriage", "uncle-003 mom-001 uncle", "aunt-003 uncle-003 aunt by marriage",
+		}},
+		{"first cousins", f.FirstCousins, []string{
+			"cousin-003 aunt-002 first cousin", "cousin-004 aunt-002 first cousin", "cousin-001 uncle-001 first cousin", "cousin-002 uncle-001 first cousin",
+			"cousin-007 aunt-004 first cousin", "cousin-008 aunt-004 first cousin", "cousin-005 uncle-003 first cousin", "cousin-006 uncle-003 first cousin",
+		}},
+		{"nieces and nephews", f.NiecesNephews, []string{"niece-001 sibling-002 niece"}},
+		{"parents-in-law", f.ParentsInLaw, []string{"fil-001 spouse-001 father-in-law", "mil-001 spouse-001 mother-in-law"}},
+		{"siblings-in-law", f.SiblingsInLaw, []string{
+			"bil-001 sibling-001 brother-in-law", "sil-001 spouse-001 sister-in-law", "sil-002 sil-001 brother-in-law",
+		}},
+		{"children-in-law", f.ChildrenInLaw, []string{"cil-001 child-001 son-in-law"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, r := range tt.got {
+			got = append(got, fmt.Sprintf("%s %s %s", r.ID, r.Via, genealogy.EnglishLabel(g, r.Relationship)))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s = %q; want %q", tt.group, got, tt.want)
+		}
+	}
+
+	// The connection runs through the via-person, so side-specific terms
+	// follow the right parent
+	for _, r := range f.Grandparents {
+		if r.ID == "gm-002" {
+			if k := genealogy.Describe(g, r.Relationship, genealogy.Hindi); k.Romanized != "nani" {
+				t.Errorf("Hindi term for gm-002 = %q; want nani", k.Romanized)
+			}
+		}
+	}
+
+	// Grandchildren and children-in-law seen from a grandparent
+	f = genealogy.Extended(g, "gm-001")
+	if len(f.Grandchildren) != 7 || len(f.ChildrenInLaw) != 3 {
+		t.Errorf("gm-001 has %d grandchildren and %d children-in-law; want 7 and 3", len(f.Grandchildren), len(f.ChildrenInLaw))
+	}
+}
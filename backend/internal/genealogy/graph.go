@@ -0,0 +1,98 @@
+// Package genealogy answers genealogical questions, such as how two persons
+// are related, over an in-memory graph of persons and relationships. The
+// graph is built from Repository data, so it works with every backend.
+package genealogy
+
+import (
+	"slices"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Graph is an immutable index of persons and their relationships. Neighbour
+// lists are sorted by ID so that searches are deterministic.
+type Graph struct {
+	persons  map[string]models.Person
+	parents  map[string][]string
+	children map[string][]string
+	spouses  map[string][]string
+	siblings map[string][]string
+}
+
+// NewGraph indexes persons and links. Links to unknown persons and duplicate
+// links are ignored.
+func NewGraph(persons []models.Person, links []models.Link) *Graph {
+	g := &Graph{
+		persons:  make(map[string]models.Person, len(persons)),
+		parents:  make(map[string][]string),
+		children: make(map[string][]string),
+		spouses:  make(map[string][]string),
+		siblings: make(map[string][]string),
+	}
+	for _, p := range persons {
+		g.persons[p.ID] = p
+	}
+
+	for _, link := range links {
+		_, sourceOK := g.persons[link.Source]
+		_, targetOK := g.persons[link.Target]
+		if !sourceOK || !targetOK || link.Source == link.Target {
+			continue
+		}
+
+		switch link.Relationship {
+		case models.RelationshipParentChild:
+			addEdge(g.children, link.Source, link.Target)
+			addEdge(g.parents, link.Target, link.Source)
+		case models.RelationshipSpouse:
+			addEdge(g.spouses, link.Source, link.Target)
+			addEdge(g.spouses, link.Target, link.Source)
+		case models.RelationshipSibling:
+			addEdge(g.siblings, link.Source, link.Target)
+			addEdge(g.siblings, link.Target, link.Source)
+		}
+	}
+
+	for _, edges := range []map[string][]string{g.parents, g.children, g.spouses, g.siblings} {
+		for _, ids := range edges {
+			slices.Sort(ids)
+		}
+	}
+	return g
+}
+
+// addEdge appends to to edges[from] unless it is already there
+func addEdge(edges map[string][]string, from, to string) {
+	if !slices.Contains(edges[from], to) {
+		edges[from] = append(edges[from], to)
+	}
+}
+
+// Person returns the person with the given ID
+func (g *Graph) Person(id string) (models.Person, bool) {
+	p, ok := g.persons[id]
+	return p, ok
+}
+
+// Has reports whether the graph contains the person
+func (g *Graph) Has(id string) bool {
+	_, ok := g.persons[id]
+	return ok
+}
+
+// Parents returns the IDs of the person's parents
+func (g *Graph) Parents(id string) []string { return g.parents[id] }
+
+// Children returns the IDs of the person's children
+func (g *Graph) Children(id string) []string { return g.children[id] }
+
+// Spouses returns the IDs of the person's spouses
+func (g *Graph) Spouses(id string) []string { return g.spouses[id] }
+
+// Siblings returns the IDs of the person's explicit SIBLING links
+func (g *Graph) Siblings(id string) []string { return g.siblings[id] }
+
+// gender returns the Gender of a person, or "" if unknown
+func (g *Graph) gender(id string) string {
+	return g.persons[id].Gender
+}
@@ -0,0 +1,274 @@
+package genealogy
+
+import (
+	"strings"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// term is a kinship term in Devanagari with its romanized form. Masculine
+// records the grammatical gender, which the possessive ka/ki agrees with.
+type term struct {
+	native    string
+	romanized string
+	masculine bool
+}
+
+func masc(native, romanized string) term { return term{native, romanized, true} }
+func fem(native, romanized string) term  { return term{native, romanized, false} }
+
+// pick chooses the masculine or feminine term for a models.Person.Gender
+func pick(gender string, male, female term) (term, bool) {
+	switch gender {
+	case "Male":
+		return male, true
+	case "Female":
+		return female, true
+	}
+	return term{}, false
+}
+
+// hindiLabel names what r.To is to r.From in Hindi. Hindi distinguishes the
+// father's side from the mother's and elder from younger relatives, so side
+// and seniority select among terms. Relationships without a term of their
+// own are spelled out hop by hop ("pita ki patni ka bhai").
+func hindiLabel(g *Graph, r *Relationship, side, seniority string) term {
+	if r.From == r.To {
+		return masc("स्वयं", "svayam")
+	}
+	if !r.Chain {
+		if t, ok := hindiTerm(g, r, side, seniority); ok {
+			return t
+		}
+	}
+	return hindiChain(g, r.Hops)
+}
+
+// hindiTerm looks up the dedicated term for r, if there is one
+func hindiTerm(g *Graph, r *Relationship, side, seniority string) (term, bool) {
+	gender := g.gender(r.To)
+	up, down := r.lines()
+	elder := seniority == SeniorityElder
+
+	switch {
+	case r.LeadingSpouse && r.TrailingSpouse:
+		if r.Up != 1 || r.Down != 1 {
+			return term{}, false
+		}
+		spouse, sibling := g.gender(up[1]), g.gender(down[1])
+		switch {
+		case spouse == "Male" && sibling == "Male" && seniority != "":
+			if elder {
+				return fem("जेठानी", "jethani"), true
+			}
+			return fem("देवरानी", "devrani"), true
+		case spouse == "Male" && sibling == "Female":
+			return masc("ननदोई", "nandoi"), true
+		case spouse == "Female" && sibling == "Female":
+			return masc("साढ़ू", "saadhu"), true
+		case spouse == "Female" && sibling == "Male":
+			return fem("सलहज", "salhaj"), true
+		}
+		return term{}, false
+
+	case r.LeadingSpouse:
+		spouse := g.gender(up[r.Up])
+		switch {
+		case r.Up == 0 && r.Down == 0:
+			return pick(gender, masc("पति", "pati"), fem("पत्नी", "patni"))
+		case r.Up == 1 && r.Down == 0:
+			return pick(gender, masc("ससुर", "sasur"), fem("सास", "saas"))
+		case r.Up == 0 && r.Down == 1:
+			return pick(gender, masc("सौतेला बेटा", "sautela beta"), fem("सौतेली बेटी", "sauteli beti"))
+		case r.Up == 1 && r.Down == 1 && spouse == "Female":
+			return pick(gender, masc("साला", "saala"), fem("साली", "saali"))
+		case r.Up == 1 && r.Down == 1 && spouse == "Male":
+			if gender == "Female" {
+				return fem("ननद", "nanad"), true
+			}
+			if gender == "Male" && seniority != "" {
+				if elder {
+					return masc("जेठ", "jeth"), true
+				}
+				return masc("देवर", "devar"), true
+			}
+		}
+		return term{}, false
+
+	case r.TrailingSpouse:
+		relative := g.gender(down[r.Down])
+		switch {
+		case r.Up == 0 && r.Down == 0:
+			return pick(gender, masc("पति", "pati"), fem("पत्नी", "patni"))
+		case r.Up == 1 && r.Down == 0:
+			return pick(gender, masc("सौतेला पिता", "sautela pita"), fem("सौतेली माँ", "sauteli maa"))
+		case r.Up == 0 && r.Down == 1:
+			return pick(gender, masc("दामाद", "damad"), fem("बहू", "bahu"))
+		case r.Up == 1 && r.Down == 1:
+			return pick(gender, masc("जीजा", "jija"), fem("भाभी", "bhabhi"))
+		case r.Up == 2 && r.Down == 1:
+			return hindiAuntUncleSpouse(side, relative, elder)
+		}
+		return term{}, false
+	}
+
+	switch {
+	case r.Down == 0:
+		parent, ok := pick(gender, masc("पिता", "pita"), fem("माता", "mata"))
+		if r.Up == 1 || !ok {
+			return parent, ok
+		}
+		var grandparent term
+		switch side {
+		case SidePaternal:
+			grandparent, _ = pick(gender, masc("दादा", "dada"), fem("दादी", "dadi"))
+		case SideMaternal:
+			grandparent, _ = pick(gender, masc("नाना", "nana"), fem("नानी", "nani"))
+		default:
+			return term{}, false
+		}
+		return withPar(grandparent, r.Up-2), true
+
+	case r.Up == 0:
+		child, ok := pick(gender, masc("बेटा", "beta"), fem("बेटी", "beti"))
+		if r.Down == 1 || !ok {
+			return child, ok
+		}
+		var grandchild term
+		switch g.gender(down[1]) {
+		case "Male":
+			grandchild, _ = pick(gender, masc("पोता", "pota"), fem("पोती", "poti"))
+		case "Female":
+			grandchild, _ = pick(gender, masc("नाती", "nati"), fem("नातिन", "natin"))
+		default:
+			return term{}, false
+		}
+		return withPar(grandchild, r.Down-2), true
+
+	case r.Up == 1 && r.Down == 1:
+		switch seniority {
+		case SeniorityElder:
+			return pick(gender, masc("बड़ा भाई", "bada bhai"), fem("बड़ी बहन", "badi behen"))
+		case SeniorityYounger:
+			return pick(gender, masc("छोटा भाई", "chhota bhai"), fem("छोटी बहन", "chhoti behen"))
+		}
+		return pick(gender, masc("भाई", "bhai"), fem("बहन", "behen"))
+
+	case r.Up == 2 && r.Down == 1:
+		switch side {
+		case SidePaternal:
+			if elder {
+				return pick(gender, masc("ताऊ", "tau"), fem("बुआ", "bua"))
+			}
+			return pick(gender, masc("चाचा", "chacha"), fem("बुआ", "bua"))
+		case SideMaternal:
+			return pick(gender, masc("मामा", "mama"), fem("मौसी", "mausi"))
+		}
+
+	case r.Up == 3 && r.Down == 1:
+		// Grandparents' siblings are addressed like grandparents
+		switch side {
+		case SidePaternal:
+			return pick(gender, masc("दादा", "dada"), fem("दादी", "dadi"))
+		case SideMaternal:
+			return pick(gender, masc("नाना", "nana"), fem("नानी", "nani"))
+		}
+
+	case r.Up == 1 && r.Down == 2:
+		switch g.gender(down[1]) {
+		case "Male":
+			return pick(gender, masc("भतीजा", "bhatija"), fem("भतीजी", "bhatiji"))
+		case "Female":
+			return pick(gender, masc("भांजा", "bhanja"), fem("भांजी", "bhanji"))
+		}
+
+	case r.Up == 2 && r.Down == 2:
+		var prefix term
+		switch parentSibling := g.gender(down[1]); {
+		case side == SidePaternal && parentSibling == "Male":
+			prefix, _ = pick(gender, masc("चचेरा", "chachera"), fem("चचेरी", "chacheri"))
+		case side == SidePaternal && parentSibling == "Female":
+			prefix, _ = pick(gender, masc("फुफेरा", "phuphera"), fem("फुफेरी", "phupheri"))
+		case side == SideMaternal && parentSibling == "Male":
+			prefix, _ = pick(gender, masc("ममेरा", "mamera"), fem("ममेरी", "mameri"))
+		case side == SideMaternal && parentSibling == "Female":
+			prefix, _ = pick(gender, masc("मौसेरा", "mausera"), fem("मौसेरी", "mauseri"))
+		default:
+			return term{}, false
+		}
+		sibling, ok := pick(gender, masc("भाई", "bhai"), fem("बहन", "behen"))
+		return term{prefix.native + " " + sibling.native, prefix.romanized + " " + sibling.romanized, sibling.masculine}, ok
+
+	case r.Up == r.Down:
+		// More distant cousins of the same generation
+		return pick(gender, masc("दूर के भाई", "door ke bhai"), fem("दूर की बहन", "door ki behen"))
+	}
+	return term{}, false
+}
+
+// hindiAuntUncleSpouse names the spouse of a parent's sibling whose gender
+// is relative
+func hindiAuntUncleSpouse(side, relative string, elder bool) (term, bool) {
+	switch {
+	case side == SidePaternal && relative == "Male" && elder:
+		return fem("ताई", "tai"), true
+	case side == SidePaternal && relative == "Male":
+		return fem("चाची", "chachi"), true
+	case side == SidePaternal && relative == "Female":
+		return masc("फूफा", "phupha"), true
+	case side == SideMaternal && relative == "Male":
+		return fem("मामी", "mami"), true
+	case side == SideMaternal && relative == "Female":
+		return masc("मौसा", "mausa"), true
+	}
+	return term{}, false
+}
+
+// withPar adds one "par" prefix per generation beyond grandparents or
+// grandchildren (pardada, parpota)
+func withPar(t term, n int) term {
+	if n <= 0 {
+		return t
+	}
+	return term{strings.Repeat("पर", n) + t.native, strings.Repeat("par", n) + t.romanized, t.masculine}
+}
+
+// hindiChain spells a path out hop by hop, with the possessive agreeing in
+// gender with the relative that follows it: "pita ki patni ka bhai"
+func hindiChain(g *Graph, hops []models.Hop) term {
+	var native, romanized []string
+	masculine := true
+	for i, hop := range hops {
+		t := hindiHop(hop.Kind, g.gender(hop.To))
+		if i > 0 {
+			if t.masculine {
+				native, romanized = append(native, "का"), append(romanized, "ka")
+			} else {
+				native, romanized = append(native, "की"), append(romanized, "ki")
+			}
+		}
+		native, romanized = append(native, t.native), append(romanized, t.romanized)
+		masculine = t.masculine
+	}
+	return term{strings.Join(native, " "), strings.Join(romanized, " "), masculine}
+}
+
+// hindiHop is the basic term for one hop, with gender-neutral words when the
+// gender is not Male or Female
+func hindiHop(kind models.HopKind, gender string) term {
+	var male, female, other term
+	switch kind {
+	case models.HopParent:
+		male, female, other = masc("पिता", "pita"), fem("माता", "mata"), masc("अभिभावक", "abhibhavak")
+	case models.HopChild:
+		male, female, other = masc("बेटा", "beta"), fem("बेटी", "beti"), fem("संतान", "santan")
+	case models.HopSpouse:
+		male, female, other = masc("पति", "pati"), fem("पत्नी", "patni"), masc("जीवनसाथी", "jeevansathi")
+	case models.HopSibling:
+		male, female, other = masc("भाई", "bhai"), fem("बहन", "behen"), masc("सहोदर", "sahodar")
+	}
+	if t, ok := pick(gender, male, female); ok {
+		return t
+	}
+	return other
+}
@@ -0,0 +1,136 @@
+package genealogy
+
+import (
+	"slices"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Language selects the kinship terminology used to name a relationship
+type Language string
+
+// Supported kinship languages
+const (
+	English Language = "en"
+	Hindi   Language = "hi"
+)
+
+// Languages lists every supported kinship language
+var Languages = []Language{English, Hindi}
+
+// ParseLanguage returns the language for a code such as "en" or "hi"
+func ParseLanguage(code string) (Language, bool) {
+	lang := Language(code)
+	return lang, slices.Contains(Languages, lang)
+}
+
+// Distinctions many kinship systems draw that English terms leave out
+const (
+	SidePaternal = "paternal"
+	SideMaternal = "maternal"
+
+	SeniorityElder   = "elder"
+	SeniorityYounger = "younger"
+)
+
+// Kinship names a relationship in one language
+type Kinship struct {
+	Language Language
+	// Term is written in the language's own script
+	Term string
+	// Romanized is a Latin transliteration of Term for non-Latin scripts
+	Romanized string
+	// Side is SidePaternal or SideMaternal for relatives reached through a
+	// parent of From, such as grandparents, aunts, uncles and cousins
+	Side string
+	// Seniority compares the birth date of To's blood relative with the
+	// person of the same generation on From's line: From and a sibling or
+	// cousin, or From's parent and an aunt or uncle
+	Seniority string
+}
+
+// Describe names what r.To is to r.From in the given language
+func Describe(g *Graph, r *Relationship, lang Language) Kinship {
+	k := Kinship{
+		Language:  lang,
+		Side:      side(g, r),
+		Seniority: seniority(g, r),
+	}
+
+	switch lang {
+	case Hindi:
+		t := hindiLabel(g, r, k.Side, k.Seniority)
+		k.Term, k.Romanized = t.native, t.romanized
+	default:
+		k.Term = EnglishLabel(g, r)
+	}
+	return k
+}
+
+// lines splits the blood part of r at the common ancestor. up[i] is the
+// person i generations below it on From's side and down[i] the one on To's
+// side, so up[r.Up] and down[r.Down] are the ends of the blood part. The
+// common ancestor up[0] == down[0] is "" when a sibling hop stands in for it.
+func (r *Relationship) lines() (up, down []string) {
+	path := r.Path()
+	first, last := 0, len(path)-1
+	if r.LeadingSpouse {
+		first++
+	}
+	if r.TrailingSpouse {
+		last--
+	}
+	nodes, hops := path[first:last+1], r.Hops[first:last]
+
+	climb := []string{nodes[0]}
+	i := 0
+	for ; i < len(hops) && hops[i].Kind == models.HopParent; i++ {
+		climb = append(climb, nodes[i+1])
+	}
+
+	down = []string{climb[len(climb)-1]}
+	if i < len(hops) && hops[i].Kind == models.HopSibling {
+		climb = append(climb, "")
+		down = []string{"", nodes[i+1]}
+		i++
+	}
+	for ; i < len(hops); i++ {
+		down = append(down, nodes[i+1])
+	}
+
+	slices.Reverse(climb)
+	return climb, down
+}
+
+// side reports whether a blood relative at least two generations up is on
+// the father's or the mother's side
+func side(g *Graph, r *Relationship) string {
+	if r.Chain || r.LeadingSpouse || r.Up < 2 {
+		return ""
+	}
+	up, _ := r.lines()
+	switch g.gender(up[r.Up-1]) {
+	case "Male":
+		return SidePaternal
+	case "Female":
+		return SideMaternal
+	}
+	return ""
+}
+
+// seniority compares the end of To's blood line with the person of the same
+// generation on From's line, when To's line is no longer than From's
+func seniority(g *Graph, r *Relationship) string {
+	if r.Chain || r.Up == 0 || r.Down == 0 || r.Down > r.Up {
+		return ""
+	}
+	up, down := r.lines()
+	ours, theirs := g.persons[up[r.Down]].BirthDate, g.persons[down[r.Down]].BirthDate
+	switch {
+	case ours == "" || theirs == "" || ours == theirs:
+		return ""
+	case theirs < ours:
+		return SeniorityElder
+	}
+	return SeniorityYounger
+}
@@ -0,0 +1,76 @@
+package genealogy_test
+
+import (
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+)
+
+func TestDescribeHindi(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	tests := []struct {
+		from, to  string
+		romanized string
+		side      string
+		seniority string
+	}{
+		{"me-001", "dad-001", "pita", "", ""},
+		{"me-001", "gp-001", "dada", genealogy.SidePaternal, ""},
+		{"me-001", "gp-002", "nana", genealogy.SideMaternal, ""},
+		{"me-001", "ggp-001", "pardada", genealogy.SidePaternal, ""},
+		{"dad-001", "child-001", "pota", "", ""},
+		{"me-001", "sibling-001", "chhoti behen", "", genealogy.SeniorityYounger},
+		{"sibling-001", "me-001", "bada bhai", "", genealogy.SeniorityElder},
+		{"me-001", "uncle-001", "chacha", genealogy.SidePaternal, genealogy.SeniorityYounger},
+		{"me-001", "aunt-002", "bua", genealogy.SidePaternal, genealogy.SeniorityYounger},
+		{"me-001", "uncle-003", "mama", genealogy.SideMaternal, genealogy.SeniorityElder},
+		{"me-001", "aunt-004", "mausi", genealogy.SideMaternal, genealogy.SeniorityYounger},
+		{"me-001", "aunt-001", "chachi", genealogy.SidePaternal, genealogy.SeniorityYounger},
+		{"me-001", "cousin-001", "chachera bhai", genealogy.SidePaternal, genealogy.SeniorityYounger},
+		{"me-001", "spouse-001", "patni", "", ""},
+		{"spouse-001", "dad-001", "sasur", "", ""},
+		{"dad-001", "spouse-001", "bahu", "", ""},
+		{"uncle-001", "me-001", "bhatija", "", ""},
+	}
+
+	for _, tt := range tests {
+		rel, ok := genealogy.Relate(g, tt.from, tt.to)
+		if !ok {
+			t.Errorf("%s -> %s: not related", tt.from, tt.to)
+			continue
+		}
+		k := genealogy.Describe(g, rel, genealogy.Hindi)
+		if k.Romanized != tt.romanized || k.Side != tt.side || k.Seniority != tt.seniority {
+			t.Errorf("%s -> %s = %q (%s), side %q, seniority %q; want %q, side %q, seniority %q",
+				tt.from, tt.to, k.Romanized, k.Term, k.Side, k.Seniority, tt.romanized, tt.side, tt.seniority)
+		}
+		if k.Term == "" || k.Language != genealogy.Hindi {
+			t.Errorf("%s -> %s: term %q, language %q", tt.from, tt.to, k.Term, k.Language)
+		}
+	}
+}
+
+func TestDescribeEnglish(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	rel, ok := genealogy.Relate(g, "me-001", "uncle-003")
+	if !ok {
+		t.Fatal("me-001 and uncle-003 not related")
+	}
+	k := genealogy.Describe(g, rel, genealogy.English)
+	if k.Term != "uncle" || k.Romanized != "" || k.Side != genealogy.SideMaternal || k.Seniority != genealogy.SeniorityElder {
+		t.Errorf("Describe = %+v; want maternal elder uncle", k)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	for _, code := range []string{"en", "hi"} {
+		if _, ok := genealogy.ParseLanguage(code); !ok {
+			t.Errorf("ParseLanguage(%q) not supported", code)
+		}
+	}
+	if _, ok := genealogy.ParseLanguage("fr"); ok {
+		t.Error(`ParseLanguage("fr") supported`)
+	}
+}
@@ -0,0 +1,39 @@
+package genealogy
+
+import "github.com/heemankverma/family_tree/backend/internal/models"
+
+// bloodKinds are the hops that follow PARENT_CHILD links only
+var bloodKinds = []models.HopKind{models.HopParent, models.HopChild}
+
+// ShortestPath finds the path with the fewest hops from one person to
+// another. With bloodOnly it follows PARENT_CHILD links only; otherwise
+// marriages and explicit sibling links are allowed too. It returns false if
+// no such path exists.
+func ShortestPath(g *Graph, from, to string, bloodOnly bool) ([]models.Hop, bool) {
+	if !g.Has(from) || !g.Has(to) {
+		return nil, false
+	}
+	if from == to {
+		return []models.Hop{}, true
+	}
+	var kinds []models.HopKind
+	if bloodOnly {
+		kinds = bloodKinds
+	}
+	return g.shortestPath(from, to, kinds)
+}
+
+// HopLink returns the relationship a hop from the given person follows, in
+// the direction it is stored: PARENT_CHILD from parent to child, SPOUSE
+// and SIBLING from the earlier person on the path to the later.
+func HopLink(from string, hop models.Hop) models.Link {
+	switch hop.Kind {
+	case models.HopParent:
+		return models.Link{Relationship: models.RelationshipParentChild, Source: hop.To, Target: from}
+	case models.HopChild:
+		return models.Link{Relationship: models.RelationshipParentChild, Source: from, Target: hop.To}
+	case models.HopSpouse:
+		return models.Link{Relationship: models.RelationshipSpouse, Source: from, Target: hop.To}
+	}
+	return models.Link{Relationship: models.RelationshipSibling, Source: from, Target: hop.To}
+}
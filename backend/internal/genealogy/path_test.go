@@ -0,0 +1,67 @@
+package genealogy_test
+
+import (
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestShortestPath(t *testing.T) {
+	g := fixtureGraph(t, []models.Person{{ID: "loner-001", Name: "Unrelated", Gender: "Other"}})
+
+	tests := []struct {
+		from, to  string
+		bloodOnly bool
+		want      []models.HopKind // nil if not connected
+	}{
+		{"me-001", "me-001", false, []models.HopKind{}},
+		{"me-001", "spouse-001", false, []models.HopKind{models.HopSpouse}},
+		{"me-001", "spouse-001", true, []models.HopKind{models.HopChild, models.HopParent}},
+		{"me-001", "aunt-001", false, []models.HopKind{models.HopParent, models.HopSibling, models.HopSpouse}},
+		{"me-001", "aunt-001", true, []models.HopKind{models.HopParent, models.HopParent, models.HopChild, models.HopChild, models.HopParent}},
+		{"me-001", "loner-001", false, nil},
+	}
+
+	for _, tt := range tests {
+		hops, ok := genealogy.ShortestPath(g, tt.from, tt.to, tt.bloodOnly)
+		if ok != (tt.want != nil) {
+			t.Errorf("%s -> %s (blood %v): found %v; want %v", tt.from, tt.to, tt.bloodOnly, ok, tt.want != nil)
+			continue
+		}
+		kinds := []models.HopKind{}
+		for _, hop := range hops {
+			kinds = append(kinds, hop.Kind)
+		}
+		if len(kinds) != len(tt.want) {
+			t.Errorf("%s -> %s (blood %v) = %v; want %v", tt.from, tt.to, tt.bloodOnly, kinds, tt.want)
+			continue
+		}
+		for i := range kinds {
+			if kinds[i] != tt.want[i] {
+				t.Errorf("%s -> %s (blood %v) = %v; want %v", tt.from, tt.to, tt.bloodOnly, kinds, tt.want)
+				break
+			}
+		}
+		if len(hops) > 0 && hops[len(hops)-1].To != tt.to {
+			t.Errorf("%s -> %s ends at %s", tt.from, tt.to, hops[len(hops)-1].To)
+		}
+	}
+}
+
+func TestHopLink(t *testing.T) {
+	tests := []struct {
+		hop  models.Hop
+		want models.Link
+	}{
+		{models.Hop{Kind: models.HopParent, To: "dad-001"}, models.Link{Relationship: models.RelationshipParentChild, Source: "dad-001", Target: "me-001"}},
+		{models.Hop{Kind: models.HopChild, To: "child-001"}, models.Link{Relationship: models.RelationshipParentChild, Source: "me-001", Target: "child-001"}},
+		{models.Hop{Kind: models.HopSpouse, To: "spouse-001"}, models.Link{Relationship: models.RelationshipSpouse, Source: "me-001", Target: "spouse-001"}},
+		{models.Hop{Kind: models.HopSibling, To: "sibling-001"}, models.Link{Relationship: models.RelationshipSibling, Source: "me-001", Target: "sibling-001"}},
+	}
+	for _, tt := range tests {
+		if got := genealogy.HopLink("me-001", tt.hop); got != tt.want {
+			t.Errorf("HopLink(%v) = %+v; want %+v", tt.hop, got, tt.want)
+		}
+	}
+}
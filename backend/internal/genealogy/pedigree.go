@@ -0,0 +1,99 @@
+package genealogy
+
+import "github.com/heemankverma/family_tree/backend/internal/models"
+
+// MaxPedigreeGenerations bounds ancestor walks. Ahnentafel numbers double
+// each generation, and beyond 2^53 JSON clients lose precision.
+const MaxPedigreeGenerations = 50
+
+// Pedigree is the ancestry of one person
+type Pedigree struct {
+	Root      *models.PedigreeNode
+	Depth     int     // deepest generation found
+	Ancestors int     // distinct ancestors
+	Collapse  bool    // some ancestor appears more than once
+	Missing   []int64 // Ahnentafel numbers of unknown parents
+}
+
+// Ancestors walks PARENT_CHILD links up from id for at most generations
+// generations, numbering each ancestor by Ahnentafel. It returns false if
+// id is not in the graph.
+func Ancestors(g *Graph, id string, generations int) (*Pedigree, bool) {
+	subject, ok := g.persons[id]
+	if !ok {
+		return nil, false
+	}
+	generations = min(generations, MaxPedigreeGenerations)
+
+	root := &models.PedigreeNode{Ahnentafel: 1, Person: subject}
+	pedigree := &Pedigree{Root: root, Missing: []int64{}}
+	first := map[string]int64{id: 1}
+
+	// Breadth first, fathers before mothers, so that a person's first
+	// appearance has the lowest number
+	queue := []*models.PedigreeNode{root}
+	for len(queue) > 0 {
+		child := queue[0]
+		queue = queue[1:]
+		if child.Generation == generations {
+			continue
+		}
+
+		father, mother := g.fatherAndMother(child.Person.ID)
+		for i, parentID := range []string{father, mother} {
+			number := 2*child.Ahnentafel + int64(i)
+			if parentID == "" {
+				pedigree.Missing = append(pedigree.Missing, number)
+				continue
+			}
+
+			node := &models.PedigreeNode{
+				Ahnentafel: number,
+				Generation: child.Generation + 1,
+				Person:     g.persons[parentID],
+			}
+			if i == 0 {
+				child.Father = node
+			} else {
+				child.Mother = node
+			}
+			pedigree.Depth = max(pedigree.Depth, node.Generation)
+
+			if repeat, seen := first[parentID]; seen {
+				node.RepeatOf = repeat
+				pedigree.Collapse = true
+				continue
+			}
+			first[parentID] = number
+			pedigree.Ancestors++
+			queue = append(queue, node)
+		}
+	}
+	return pedigree, true
+}
+
+// fatherAndMother assigns the parents of id to the father and mother slots
+// by gender. Parents of other or matching genders fill whichever slot is
+// left, and any parents beyond two are ignored.
+func (g *Graph) fatherAndMother(id string) (father, mother string) {
+	var rest []string
+	for _, p := range g.parents[id] {
+		switch {
+		case father == "" && g.gender(p) == "Male":
+			father = p
+		case mother == "" && g.gender(p) == "Female":
+			mother = p
+		default:
+			rest = append(rest, p)
+		}
+	}
+	for _, p := range rest {
+		switch {
+		case father == "":
+			father = p
+		case mother == "":
+			mother = p
+		}
+	}
+	return father, mother
+}
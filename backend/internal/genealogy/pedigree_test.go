@@ -0,0 +1,105 @@
+package genealogy_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// ahnentafel flattens a pedigree into number -> person ID, with repeats
+// recorded as "=<first number>"
+func ahnentafel(node *models.PedigreeNode, numbers map[int64]string) {
+	if node == nil {
+		return
+	}
+	numbers[node.Ahnentafel] = node.Person.ID
+	if node.RepeatOf != 0 {
+		numbers[node.Ahnentafel] = "=" + node.Person.ID
+	}
+	ahnentafel(node.Father, numbers)
+	ahnentafel(node.Mother, numbers)
+}
+
+func TestAncestors(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	pedigree, ok := genealogy.Ancestors(g, "me-001", 3)
+	if !ok {
+		t.Fatal("me-001 not found")
+	}
+
+	got := make(map[int64]string)
+	ahnentafel(pedigree.Root, got)
+	want := map[int64]string{
+		1: "me-001", 2: "dad-001", 3: "mom-001",
+		4: "gp-001", 5: "gm-001", 6: "gp-002", 7: "gm-002",
+		8: "ggp-001", 9: "ggm-001", 12: "ggp-002", 13: "ggm-002",
+	}
+	for n, id := range want {
+		if got[n] != id {
+			t.Errorf("ahnentafel %d = %q; want %q", n, got[n], id)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("pedigree = %v; want %v", got, want)
+	}
+
+	if pedigree.Depth != 3 || pedigree.Ancestors != 10 {
+		t.Errorf("depth %d, ancestors %d; want 3, 10", pedigree.Depth, pedigree.Ancestors)
+	}
+	if pedigree.Collapse {
+		t.Error("pedigree collapse flagged without repeated ancestors")
+	}
+	if !slices.Equal(pedigree.Missing, []int64{10, 11, 14, 15}) {
+		t.Errorf("missing = %v; want [10 11 14 15]", pedigree.Missing)
+	}
+	if node := pedigree.Root.Mother.Father; node.Generation != 2 || node.Person.Name == "" {
+		t.Errorf("ahnentafel 6 = %+v; want gp-002 in generation 2", node)
+	}
+}
+
+func TestAncestorsGenerationLimit(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	pedigree, _ := genealogy.Ancestors(g, "me-001", 1)
+	if pedigree.Depth != 1 || pedigree.Ancestors != 2 || len(pedigree.Missing) != 0 {
+		t.Errorf("depth %d, ancestors %d, missing %v; want 1, 2, []", pedigree.Depth, pedigree.Ancestors, pedigree.Missing)
+	}
+	if pedigree.Root.Father.Father != nil {
+		t.Error("grandfather listed beyond the generation limit")
+	}
+
+	if _, ok := genealogy.Ancestors(g, "nobody", 3); ok {
+		t.Error("Ancestors found an unknown person")
+	}
+}
+
+func TestAncestorsPedigreeCollapse(t *testing.T) {
+	// A child of first cousins has the same grandparents twice
+	g := fixtureGraph(t,
+		[]models.Person{{ID: "kid-001", Name: "Kid", Gender: "Female", BirthDate: "2015-01-01"}},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "me-001", Target: "kid-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "cousin-002", Target: "kid-001"},
+	)
+
+	pedigree, _ := genealogy.Ancestors(g, "kid-001", 4)
+	got := make(map[int64]string)
+	ahnentafel(pedigree.Root, got)
+
+	for n, id := range map[int64]string{8: "gp-001", 9: "gm-001", 12: "=gp-001", 13: "=gm-001", 16: "ggp-001"} {
+		if got[n] != id {
+			t.Errorf("ahnentafel %d = %q; want %q", n, got[n], id)
+		}
+	}
+	if id, listed := got[24]; listed {
+		t.Errorf("ancestors of a repeated person listed again: 24 = %q", id)
+	}
+	if !pedigree.Collapse {
+		t.Error("pedigree collapse not flagged")
+	}
+	if node := pedigree.Root.Mother.Father.Father; node.RepeatOf != 8 {
+		t.Errorf("ahnentafel 12 repeats %d; want 8", node.RepeatOf)
+	}
+}
@@ -0,0 +1,295 @@
+package genealogy
+
+import (
+	"container/heap"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Relationship is the closest nameable connection from one person to another.
+// Most connections are a blood part, climbing Up generations to a common
+// ancestor and descending Down generations from it, optionally preceded or
+// followed by one marriage. A sibling hop counts as one generation each way.
+type Relationship struct {
+	From string
+	To   string
+	Hops []models.Hop
+
+	Up             int
+	Down           int
+	LeadingSpouse  bool // the blood part starts at From's spouse
+	TrailingSpouse bool // To is the spouse of From's blood relative
+	Half           bool // siblings sharing exactly one known parent
+
+	// Chain is set when the connection does not fit the pattern above and
+	// Hops is simply the shortest path
+	Chain bool
+}
+
+// Path returns the IDs of every person on the connection, From first
+func (r *Relationship) Path() []string {
+	path := []string{r.From}
+	for _, hop := range r.Hops {
+		path = append(path, hop.To)
+	}
+	return path
+}
+
+// Relate finds how to is related to from. It prefers blood relationships,
+// then a single marriage on either side, then both, and finally falls back
+// to the shortest chain of any relationships. It returns false if the two
+// persons are not connected.
+func Relate(g *Graph, from, to string) (*Relationship, bool) {
+	if !g.Has(from) || !g.Has(to) {
+		return nil, false
+	}
+	if from == to {
+		return &Relationship{From: from, To: to}, true
+	}
+
+	if hops, _, ok := g.search(from, to, pattern{}); ok {
+		return g.newRelationship(from, to, hops, pattern{}), true
+	}
+
+	// A marriage on one side or both: keep the closest blood part
+	var best *Relationship
+	bestCost := 0
+	for _, p := range []pattern{{trailingSpouse: true}, {leadingSpouse: true}, {leadingSpouse: true, trailingSpouse: true}} {
+		if hops, cost, ok := g.search(from, to, p); ok && (best == nil || cost < bestCost) {
+			best, bestCost = g.newRelationship(from, to, hops, p), cost
+		}
+	}
+	if best != nil {
+		return best, true
+	}
+
+	if hops, ok := g.shortestPath(from, to, nil); ok {
+		return &Relationship{From: from, To: to, Hops: hops, Chain: true}, true
+	}
+	return nil, false
+}
+
+// newRelationship counts the generations of a path found for p
+func (g *Graph) newRelationship(from, to string, hops []models.Hop, p pattern) *Relationship {
+	r := &Relationship{
+		From:           from,
+		To:             to,
+		Hops:           hops,
+		LeadingSpouse:  p.leadingSpouse,
+		TrailingSpouse: p.trailingSpouse,
+	}
+	for _, hop := range hops {
+		switch hop.Kind {
+		case models.HopParent:
+			r.Up++
+		case models.HopChild:
+			r.Down++
+		case models.HopSibling:
+			r.Up++
+			r.Down++
+		}
+	}
+
+	if r.Up == 1 && r.Down == 1 {
+		up, down := r.lines()
+		r.Half = g.halfSiblings(up[1], down[1])
+	}
+	return r
+}
+
+// halfSiblings reports whether a and b share exactly one parent while at
+// least one of them has two known parents
+func (g *Graph) halfSiblings(a, b string) bool {
+	shared := 0
+	for _, p := range g.parents[a] {
+		for _, q := range g.parents[b] {
+			if p == q {
+				shared++
+			}
+		}
+	}
+	return shared == 1 && (len(g.parents[a]) == 2 || len(g.parents[b]) == 2)
+}
+
+// pattern restricts a search to a blood part, optionally preceded and
+// followed by a spouse hop
+type pattern struct {
+	leadingSpouse  bool
+	trailingSpouse bool
+}
+
+// phase is the position of a search within its pattern
+type phase int
+
+const (
+	phaseStart phase = iota // before the leading spouse hop
+	phaseUp                 // climbing towards the common ancestor
+	phaseDown               // descending from the common ancestor
+	phaseEnd                // after the trailing spouse hop
+)
+
+// state is a person reached in a phase of the search
+type state struct {
+	id    string
+	phase phase
+}
+
+// move is a hop allowed from a state
+type move struct {
+	hop         models.Hop
+	phase       phase
+	generations int
+}
+
+// moves lists the hops p allows from s
+func (g *Graph) moves(p pattern, s state) []move {
+	var moves []move
+	add := func(kind models.HopKind, ids []string, next phase, generations int) {
+		for _, id := range ids {
+			moves = append(moves, move{models.Hop{Kind: kind, To: id}, next, generations})
+		}
+	}
+
+	switch s.phase {
+	case phaseStart:
+		add(models.HopSpouse, g.spouses[s.id], phaseUp, 0)
+	case phaseUp:
+		add(models.HopParent, g.parents[s.id], phaseUp, 1)
+		add(models.HopSibling, g.siblings[s.id], phaseDown, 2)
+		add(models.HopChild, g.children[s.id], phaseDown, 1)
+	case phaseDown:
+		add(models.HopChild, g.children[s.id], phaseDown, 1)
+	}
+	if p.trailingSpouse && (s.phase == phaseUp || s.phase == phaseDown) {
+		add(models.HopSpouse, g.spouses[s.id], phaseEnd, 0)
+	}
+	return moves
+}
+
+// accepts reports whether a search for p may stop at s
+func (p pattern) accepts(s state) bool {
+	if p.trailingSpouse {
+		return s.phase == phaseEnd
+	}
+	return s.phase == phaseUp || s.phase == phaseDown
+}
+
+// search finds the path from one person to another that fits p with the
+// fewest generations, then the fewest hops. The cost orders paths that way.
+func (g *Graph) search(from, to string, p pattern) ([]models.Hop, int, bool) {
+	start := state{id: from, phase: phaseUp}
+	if p.leadingSpouse {
+		start.phase = phaseStart
+	}
+
+	type entry struct {
+		prev state
+		hop  models.Hop
+		cost int
+	}
+	best := map[state]entry{start: {}}
+	done := make(map[state]bool)
+	queue := &stateQueue{{start, 0}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(queuedState)
+		s := item.state
+		if done[s] {
+			continue
+		}
+		done[s] = true
+
+		if s.id == to && p.accepts(s) {
+			var hops []models.Hop
+			for s != start {
+				e := best[s]
+				hops = append([]models.Hop{e.hop}, hops...)
+				s = e.prev
+			}
+			return hops, item.cost, true
+		}
+
+		for _, m := range g.moves(p, s) {
+			next := state{id: m.hop.To, phase: m.phase}
+			cost := item.cost + m.generations*1000 + 1
+			if e, seen := best[next]; seen && e.cost <= cost {
+				continue
+			}
+			best[next] = entry{prev: s, hop: m.hop, cost: cost}
+			heap.Push(queue, queuedState{next, cost})
+		}
+	}
+	return nil, 0, false
+}
+
+// queuedState is a state waiting in the search queue
+type queuedState struct {
+	state state
+	cost  int
+}
+
+// stateQueue is a min-heap of states by cost
+type stateQueue []queuedState
+
+func (q stateQueue) Len() int           { return len(q) }
+func (q stateQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q stateQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *stateQueue) Push(x any)        { *q = append(*q, x.(queuedState)) }
+func (q *stateQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// shortestPath finds the path with the fewest hops over the given kinds of
+// relationship, or over all of them if kinds is empty
+func (g *Graph) shortestPath(from, to string, kinds []models.HopKind) ([]models.Hop, bool) {
+	if len(kinds) == 0 {
+		kinds = []models.HopKind{models.HopParent, models.HopChild, models.HopSpouse, models.HopSibling}
+	}
+
+	prev := map[string]models.Hop{from: {}}
+	cameFrom := map[string]string{}
+	frontier := []string{from}
+	for len(frontier) > 0 {
+		var next []string
+		for _, id := range frontier {
+			for _, kind := range kinds {
+				for _, n := range g.neighbours(id, kind) {
+					if _, seen := prev[n]; seen {
+						continue
+					}
+					prev[n] = models.Hop{Kind: kind, To: n}
+					cameFrom[n] = id
+					if n == to {
+						var hops []models.Hop
+						for n != from {
+							hops = append([]models.Hop{prev[n]}, hops...)
+							n = cameFrom[n]
+						}
+						return hops, true
+					}
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+	return nil, false
+}
+
+// neighbours returns the persons reached from id by one hop of kind
+func (g *Graph) neighbours(id string, kind models.HopKind) []string {
+	switch kind {
+	case models.HopParent:
+		return g.parents[id]
+	case models.HopChild:
+		return g.children[id]
+	case models.HopSpouse:
+		return g.spouses[id]
+	case models.HopSibling:
+		return g.siblings[id]
+	}
+	return nil
+}
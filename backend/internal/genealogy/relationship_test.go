@@ -0,0 +1,78 @@
+package genealogy_test
+
+import (
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// fixtureGraph builds a graph from data/example_*.csv plus extra links
+func fixtureGraph(t *testing.T, persons []models.Person, links ...models.Link) *genealogy.Graph {
+	t.Helper()
+	fx := repotest.LoadFixtures(t)
+	return genealogy.NewGraph(append(fx.Persons, persons...), append(fx.Links, links...))
+}
+
+func TestEnglishLabel(t *testing.T) {
+	extra := []models.Person{
+		{ID: "half-001", Name: "Half Sibling", Gender: "Female", BirthDate: "1995-01-01"},
+		{ID: "step-001", Name: "Step Mother", Gender: "Female", BirthDate: "1960-01-01"},
+		{ID: "step-002", Name: "Step Brother", Gender: "Male", BirthDate: "1990-01-01"},
+		{ID: "loner-001", Name: "Unrelated", Gender: "Other", BirthDate: "1990-01-01"},
+	}
+	g := fixtureGraph(t, extra,
+		models.Link{Relationship: models.RelationshipParentChild, Source: "dad-001", Target: "half-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "step-001", Target: "half-001"},
+		models.Link{Relationship: models.RelationshipSpouse, Source: "dad-001", Target: "step-001"},
+		models.Link{Relationship: models.RelationshipParentChild, Source: "step-001", Target: "step-002"},
+	)
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"me-001", "me-001", "self"},
+		{"me-001", "dad-001", "father"},
+		{"me-001", "gm-001", "grandmother"},
+		{"me-001", "ggp-001", "great-grandfather"},
+		{"dad-001", "me-001", "son"},
+		{"ggm-001", "child-001", "great-great-grandson"},
+		{"me-001", "sibling-001", "sister"},
+		{"me-001", "half-001", "half-sister"},
+		{"me-001", "uncle-001", "uncle"},
+		{"child-001", "aunt-002", "great-aunt"},
+		{"dad-001", "child-001", "grandson"},
+		{"uncle-001", "me-001", "nephew"},
+		{"aunt-002", "child-001", "grandnephew"},
+		{"me-001", "cousin-001", "first cousin"},
+		{"me-001", "child-003", "first cousin once removed"},
+		{"child-001", "child-003", "second cousin"},
+		{"child-001", "cousin-001", "first cousin once removed"},
+		{"me-001", "spouse-001", "wife"},
+		{"spouse-001", "dad-001", "father-in-law"},
+		{"spouse-001", "sibling-002", "brother-in-law"},
+		{"dad-001", "spouse-001", "daughter-in-law"},
+		{"me-001", "aunt-001", "aunt by marriage"},
+		{"me-001", "step-001", "step-mother"},
+		{"step-001", "me-001", "step-son"},
+		{"me-001", "step-002", "step-brother"},
+		{"me-001", "loner-001", ""},
+	}
+
+	for _, tt := range tests {
+		rel, ok := genealogy.Relate(g, tt.from, tt.to)
+		got := ""
+		if ok {
+			got = genealogy.EnglishLabel(g, rel)
+		}
+		if got != tt.want {
+			path := []string(nil)
+			if ok {
+				path = rel.Path()
+			}
+			t.Errorf("%s -> %s = %q (path %v); want %q", tt.from, tt.to, got, path, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package genealogy
+
+import "slices"
+
+// Scope selects the part of the family around a person that Subtree returns
+type Scope string
+
+// Supported scopes
+const (
+	ScopeAncestors   Scope = "ancestors"
+	ScopeDescendants Scope = "descendants"
+)
+
+// Scopes lists every supported scope
+var Scopes = []Scope{ScopeAncestors, ScopeDescendants}
+
+// ParseScope returns the scope for a name such as "ancestors"
+func ParseScope(name string) (Scope, bool) {
+	scope := Scope(name)
+	return scope, slices.Contains(Scopes, scope)
+}
+
+// Subtree returns the IDs of id and its ancestors, or of id, its
+// descendants and the descendants' spouses, so that both parents of every
+// descendant are included where known
+func Subtree(g *Graph, id string, scope Scope) map[string]bool {
+	ids := map[string]bool{id: true}
+	if !g.Has(id) {
+		return ids
+	}
+
+	next := g.parents
+	if scope == ScopeDescendants {
+		next = g.children
+	}
+	line := []string{id}
+	for i := 0; i < len(line); i++ {
+		for _, relative := range next[line[i]] {
+			if !ids[relative] {
+				ids[relative] = true
+				line = append(line, relative)
+			}
+		}
+	}
+
+	if scope == ScopeDescendants {
+		for _, descendant := range line {
+			for _, spouse := range g.spouses[descendant] {
+				ids[spouse] = true
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,34 @@
+package genealogy_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+)
+
+func TestSubtree(t *testing.T) {
+	g := fixtureGraph(t, nil)
+
+	tests := []struct {
+		id    string
+		scope genealogy.Scope
+		want  []string
+	}{
+		{"dad-001", genealogy.ScopeAncestors, []string{"dad-001", "ggm-001", "ggp-001", "gm-001", "gp-001"}},
+		{"uncle-001", genealogy.ScopeDescendants, []string{"aunt-001", "child-003", "cousin-001", "cousin-002", "uncle-001"}},
+		{"missing-001", genealogy.ScopeAncestors, []string{"missing-001"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for id := range genealogy.Subtree(g, tt.id, tt.scope) {
+			got = append(got, id)
+		}
+		slices.Sort(got)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Subtree(%s, %s) = %v; want %v", tt.id, tt.scope, got, tt.want)
+		}
+	}
+}
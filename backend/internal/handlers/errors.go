@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// respondRepoError writes the error response for a failed repository call.
+// Known repository errors map to their own status and code; anything else
+// is a 500 with the given code and message.
+func respondRepoError(c *gin.Context, err error, code, message string) {
+	c.JSON(repoErrorResponse(err, code, message))
+}
+
+// repoErrorResponse is the status and body respondRepoError writes
+func repoErrorResponse(err error, code, message string) (int, models.ErrorResponse) {
+	status, detail := http.StatusInternalServerError, models.ErrorDetail{Code: code, Message: message}
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		status, detail = http.StatusNotFound, models.ErrorDetail{Code: "NOT_FOUND", Message: "Not found"}
+	case errors.Is(err, database.ErrAlreadyExists):
+		status, detail = http.StatusConflict, models.ErrorDetail{Code: "ALREADY_EXISTS", Message: "Already exists"}
+	case errors.Is(err, database.ErrCycle):
+		status, detail = http.StatusConflict, models.ErrorDetail{Code: "ANCESTOR_CYCLE", Message: "A person cannot be their own ancestor"}
+	case errors.Is(err, database.ErrInvalidInput):
+		status, detail = http.StatusBadRequest, models.ErrorDetail{Code: "INVALID_INPUT", Message: "The database rejected the request"}
+	case errors.Is(err, database.ErrUnavailable):
+		status, detail = http.StatusServiceUnavailable, models.ErrorDetail{Code: "DATABASE_UNAVAILABLE", Message: "The database is unavailable. Please try again later."}
+	case errors.Is(err, database.ErrTimeout):
+		status, detail = http.StatusGatewayTimeout, models.ErrorDetail{Code: "DATABASE_TIMEOUT", Message: "The database took too long to respond"}
+	case errors.Is(err, database.ErrNotSupported):
+		status, detail = http.StatusNotImplemented, models.ErrorDetail{Code: "NOT_SUPPORTED", Message: "Not supported by the configured storage backend"}
+	}
+	detail.Details = map[string]string{"error": err.Error()}
+	return status, models.ErrorResponse{Error: detail}
+}
+
+// respondInvalidFields writes the error response for a request body that
+// failed model validation
+func respondInvalidFields(c *gin.Context, errs []fieldError) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "VALIDATION_FAILED",
+			Message: "Request body contains invalid fields",
+			Details: map[string]interface{}{"fields": errs},
+		},
+	})
+}
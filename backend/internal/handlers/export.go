@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/gedcom"
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+	"github.com/heemankverma/family_tree/backend/internal/snapshot"
+)
+
+// ExportHandler handles downloads of the family tree in exchange formats
+type ExportHandler struct {
+	repo database.Repository
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(repo database.Repository) *ExportHandler {
+	return &ExportHandler{repo: repo}
+}
+
+// ExportGEDCOM handles GET /api/export/gedcom
+// Query params: version (optional, "5.5.1" or "7.0", default "5.5.1"),
+// person and scope (optional, together: "ancestors" or "descendants" of person)
+func (h *ExportHandler) ExportGEDCOM(c *gin.Context) {
+	version, ok := gedcom.ParseVersion(c.DefaultQuery("version", string(gedcom.Version551)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "Unsupported GEDCOM version",
+				Details: map[string]interface{}{"supported_versions": gedcom.Versions},
+			},
+		})
+		return
+	}
+
+	persons, links, ok := h.loadScope(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gedcom.Encode(&buf, persons, links, version); err != nil {
+		respondRepoError(c, err, "EXPORT_ERROR", "Failed to write GEDCOM")
+		return
+	}
+
+	contentType := "application/x-gedcom"
+	if version == gedcom.Version70 {
+		contentType = "text/vnd.familysearch.gedcom"
+	}
+	c.Header("Content-Disposition", `attachment; filename="family-tree.ged"`)
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", buf.Bytes())
+}
+
+// ExportPersonsCSV handles GET /api/export/persons.csv, in the format
+// POST /api/upload reads
+// Query params: person and scope (optional, as for ExportGEDCOM),
+// living (optional, "true" or "false" to keep only living or deceased persons)
+func (h *ExportHandler) ExportPersonsCSV(c *gin.Context) {
+	persons, _, ok := h.loadFiltered(c)
+	if !ok {
+		return
+	}
+	sendCSV(c, "persons.csv", func(w io.Writer) error { return csvdata.WritePersons(w, persons) })
+}
+
+// ExportRelationshipsCSV handles GET /api/export/relationships.csv, in the
+// format POST /api/upload/relationships reads. Only relationships between
+// exported persons are included.
+// Query params: as for ExportPersonsCSV
+func (h *ExportHandler) ExportRelationshipsCSV(c *gin.Context) {
+	_, links, ok := h.loadFiltered(c)
+	if !ok {
+		return
+	}
+	sendCSV(c, "relationships.csv", func(w io.Writer) error { return csvdata.WriteRelationships(w, links) })
+}
+
+// sendCSV writes a CSV download
+func sendCSV(c *gin.Context, filename string, write func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		respondRepoError(c, err, "EXPORT_ERROR", "Failed to write CSV")
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// ExportSnapshot handles GET /api/export/snapshot, a backup of every person
+// and relationship that POST /api/upload/snapshot restores. The route must
+// be protected with middleware.AdminAuth.
+func (h *ExportHandler) ExportSnapshot(c *gin.Context) {
+	persons, err := h.repo.GetAllPersons(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to load persons")
+		return
+	}
+
+	links, err := h.repo.GetAllRelationships(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to load relationships")
+		return
+	}
+
+	s := snapshot.New(persons, links, time.Now())
+	filename := "family-tree-" + s.ExportedAt.Format("20060102T150405Z") + ".json"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.JSON(http.StatusOK, s)
+}
+
+// loadFiltered is loadScope followed by the living query parameter, which
+// keeps only living ("true") or deceased ("false") persons and the
+// relationships between them. On failure it writes the error response and
+// returns false.
+func (h *ExportHandler) loadFiltered(c *gin.Context) ([]models.Person, []models.Link, bool) {
+	value := c.Query("living")
+	living, err := strconv.ParseBool(value)
+	if value != "" && err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "living must be true or false",
+			},
+		})
+		return nil, nil, false
+	}
+
+	persons, links, ok := h.loadScope(c)
+	if !ok || value == "" {
+		return persons, links, ok
+	}
+
+	ids := make(map[string]bool, len(persons))
+	kept := make([]models.Person, 0, len(persons))
+	for _, p := range persons {
+		if p.IsAlive == living {
+			ids[p.ID] = true
+			kept = append(kept, p)
+		}
+	}
+	return kept, linksWithin(links, ids), true
+}
+
+// loadScope loads every person and relationship, or with the person and
+// scope query parameters only those within the ancestors or descendants of
+// person. On failure it writes the error response and returns false.
+func (h *ExportHandler) loadScope(c *gin.Context) ([]models.Person, []models.Link, bool) {
+	id, scopeName := c.Query("person"), c.Query("scope")
+	scope, ok := genealogy.ParseScope(scopeName)
+	switch {
+	case id == "" && scopeName == "":
+	case id == "" || scopeName == "":
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "person and scope must be given together",
+			},
+		})
+		return nil, nil, false
+	case !ok:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "Unsupported scope",
+				Details: map[string]interface{}{"supported_scopes": genealogy.Scopes},
+			},
+		})
+		return nil, nil, false
+	}
+
+	persons, err := h.repo.GetAllPersons(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to load persons")
+		return nil, nil, false
+	}
+
+	links, err := h.repo.GetAllRelationships(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to load relationships")
+		return nil, nil, false
+	}
+
+	if id == "" {
+		return persons, links, true
+	}
+
+	g := genealogy.NewGraph(persons, links)
+	if !g.Has(id) {
+		respondPersonNotFound(c, id)
+		return nil, nil, false
+	}
+
+	ids := genealogy.Subtree(g, id, scope)
+	scoped := make([]models.Person, 0, len(ids))
+	for _, p := range persons {
+		if ids[p.ID] {
+			scoped = append(scoped, p)
+		}
+	}
+	return scoped, linksWithin(links, ids), true
+}
+
+// linksWithin returns the links whose endpoints are both in ids
+func linksWithin(links []models.Link, ids map[string]bool) []models.Link {
+	within := make([]models.Link, 0, len(links))
+	for _, link := range links {
+		if ids[link.Source] && ids[link.Target] {
+			within = append(within, link)
+		}
+	}
+	return within
+}
@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/genealogy"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// GenealogyHandler answers genealogical questions about the whole family
+// graph. The graph is loaded from the repository on every request, so it
+// works the same on every storage backend.
+type GenealogyHandler struct {
+	repo database.Repository
+}
+
+// NewGenealogyHandler creates a new genealogy handler
+func NewGenealogyHandler(repo database.Repository) *GenealogyHandler {
+	return &GenealogyHandler{repo: repo}
+}
+
+// GetRelationship handles GET /api/relationship
+// Query params: from, to (required person IDs), lang (optional, "en" or "hi", default "en")
+func (h *GenealogyHandler) GetRelationship(c *gin.Context) {
+	from, to, ok := requirePersonPair(c)
+	if !ok {
+		return
+	}
+
+	lang, ok := languageParam(c)
+	if !ok {
+		return
+	}
+
+	g, ok := h.loadGraph(c, from, to)
+	if !ok {
+		return
+	}
+
+	response := models.RelationshipResponse{
+		From:     from,
+		To:       to,
+		Language: string(lang),
+		Label:    "not related",
+		Path:     []string{},
+		Hops:     []models.Hop{},
+	}
+	if rel, related := genealogy.Relate(g, from, to); related {
+		kinship := genealogy.Describe(g, rel, lang)
+		response.Related = true
+		response.Label = kinship.Term
+		response.Romanized = kinship.Romanized
+		response.Side = kinship.Side
+		response.Seniority = kinship.Seniority
+		response.Path = rel.Path()
+		response.Hops = append(response.Hops, rel.Hops...)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRelatedness handles GET /api/relatedness
+// Query params: from, to (required person IDs)
+func (h *GenealogyHandler) GetRelatedness(c *gin.Context) {
+	from, to, ok := requirePersonPair(c)
+	if !ok {
+		return
+	}
+
+	g, ok := h.loadGraph(c, from, to)
+	if !ok {
+		return
+	}
+
+	coefficients := genealogy.NewCoefficients(g)
+	c.JSON(http.StatusOK, models.RelatednessResponse{
+		From:         from,
+		To:           to,
+		Relationship: coefficients.Relationship(from, to),
+		Kinship:      coefficients.Kinship(from, to),
+		Inbreeding: map[string]float64{
+			from: coefficients.Inbreeding(from),
+			to:   coefficients.Inbreeding(to),
+		},
+	})
+}
+
+// GetPath handles GET /api/path
+// Query params: from, to (required person IDs), blood (optional, "true" to
+// follow PARENT_CHILD links only)
+func (h *GenealogyHandler) GetPath(c *gin.Context) {
+	from, to, ok := requirePersonPair(c)
+	if !ok {
+		return
+	}
+	bloodOnly, _ := strconv.ParseBool(c.DefaultQuery("blood", "false"))
+
+	g, ok := h.loadGraph(c, from, to)
+	if !ok {
+		return
+	}
+
+	response := models.PathResponse{
+		From:      from,
+		To:        to,
+		BloodOnly: bloodOnly,
+		Persons:   []models.Person{},
+		Links:     []models.Link{},
+	}
+	if hops, found := genealogy.ShortestPath(g, from, to, bloodOnly); found {
+		person, _ := g.Person(from)
+		response.Found = true
+		response.Length = len(hops)
+		response.Persons = append(response.Persons, person)
+		for _, hop := range hops {
+			response.Links = append(response.Links, genealogy.HopLink(person.ID, hop))
+			person, _ = g.Person(hop.To)
+			response.Persons = append(response.Persons, person)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCommonAncestors handles GET /api/common-ancestors
+// Query params: ids (two or more person IDs, comma-separated or repeated)
+func (h *GenealogyHandler) GetCommonAncestors(c *gin.Context) {
+	var ids []string
+	for _, value := range c.QueryArray("ids") {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) < 2 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "At least two distinct person IDs are required",
+			},
+		})
+		return
+	}
+
+	g, ok := h.loadGraph(c, ids...)
+	if !ok {
+		return
+	}
+
+	response := models.CommonAncestorsResponse{
+		Persons:   ids,
+		Ancestors: []models.CommonAncestor{},
+		MRCA:      []models.CommonAncestor{},
+	}
+	for _, shared := range genealogy.CommonAncestors(g, ids) {
+		person, _ := g.Person(shared.ID)
+		ancestor := models.CommonAncestor{
+			Person:    person,
+			Distances: make(map[string]int, len(ids)),
+			MRCA:      shared.MRCA,
+		}
+		for i, id := range ids {
+			ancestor.Distances[id] = shared.Distances[i]
+		}
+		response.Ancestors = append(response.Ancestors, ancestor)
+		if shared.MRCA {
+			response.MRCA = append(response.MRCA, ancestor)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAncestors handles GET /api/person/:id/ancestors
+// Query params: generations (optional, default and max genealogy.MaxPedigreeGenerations)
+func (h *GenealogyHandler) GetAncestors(c *gin.Context) {
+	id := c.Param("id")
+	generations := generationsParam(c)
+
+	g, ok := h.loadGraph(c, id)
+	if !ok {
+		return
+	}
+
+	pedigree, _ := genealogy.Ancestors(g, id, generations)
+	c.JSON(http.StatusOK, models.AncestorsResponse{
+		Subject:          id,
+		Generations:      generations,
+		Depth:            pedigree.Depth,
+		Ancestors:        pedigree.Ancestors,
+		PedigreeCollapse: pedigree.Collapse,
+		Inbreeding:       genealogy.NewCoefficients(g).Inbreeding(id),
+		Pedigree:         pedigree.Root,
+		Missing:          pedigree.Missing,
+	})
+}
+
+// GetDescendants handles GET /api/person/:id/descendants
+// Query params: generations (optional, default and max genealogy.MaxPedigreeGenerations),
+// numbering (optional, "daboville" or "henry", default "daboville")
+func (h *GenealogyHandler) GetDescendants(c *gin.Context) {
+	id := c.Param("id")
+	generations := generationsParam(c)
+
+	numbering, ok := genealogy.ParseNumbering(c.DefaultQuery("numbering", string(genealogy.DAboville)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "Unsupported numbering",
+				Details: map[string]interface{}{"supported_numberings": genealogy.Numberings},
+			},
+		})
+		return
+	}
+
+	g, ok := h.loadGraph(c, id)
+	if !ok {
+		return
+	}
+
+	descent, _ := genealogy.Descendants(g, id, generations, numbering)
+	c.JSON(http.StatusOK, models.DescendantsResponse{
+		Subject:     id,
+		Generations: generations,
+		Numbering:   string(numbering),
+		Depth:       descent.Depth,
+		Descendants: descent.Descendants,
+		Tree:        descent.Root,
+	})
+}
+
+// GetExtendedFamily handles GET /api/person/:id/extended-family
+// Query params: lang (optional, "en" or "hi", default "en")
+func (h *GenealogyHandler) GetExtendedFamily(c *gin.Context) {
+	id := c.Param("id")
+	lang, ok := languageParam(c)
+	if !ok {
+		return
+	}
+
+	g, ok := h.loadGraph(c, id)
+	if !ok {
+		return
+	}
+
+	describe := func(relatives []genealogy.Relative) []models.ExtendedRelative {
+		out := make([]models.ExtendedRelative, 0, len(relatives))
+		for _, r := range relatives {
+			person, _ := g.Person(r.ID)
+			via, _ := g.Person(r.Via)
+			kinship := genealogy.Describe(g, r.Relationship, lang)
+			out = append(out, models.ExtendedRelative{
+				Person:    person,
+				Label:     kinship.Term,
+				Romanized: kinship.Romanized,
+				Via:       via,
+			})
+		}
+		return out
+	}
+
+	person, _ := g.Person(id)
+	family := genealogy.Extended(g, id)
+	c.JSON(http.StatusOK, models.ExtendedFamilyResponse{
+		Person:        person,
+		Language:      string(lang),
+		Grandparents:  describe(family.Grandparents),
+		Grandchildren: describe(family.Grandchildren),
+		AuntsUncles:   describe(family.AuntsUncles),
+		FirstCousins:  describe(family.FirstCousins),
+		NiecesNephews: describe(family.NiecesNephews),
+		ParentsInLaw:  describe(family.ParentsInLaw),
+		SiblingsInLaw: describe(family.SiblingsInLaw),
+		ChildrenInLaw: describe(family.ChildrenInLaw),
+	})
+}
+
+// languageParam reads the lang query parameter, defaulting to English. On
+// failure it writes the error response and returns false.
+func languageParam(c *gin.Context) (genealogy.Language, bool) {
+	lang, ok := genealogy.ParseLanguage(c.DefaultQuery("lang", string(genealogy.English)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "Unsupported language",
+				Details: map[string]interface{}{"supported_languages": genealogy.Languages},
+			},
+		})
+	}
+	return lang, ok
+}
+
+// generationsParam reads the generations query parameter. Like the tree
+// depth, missing or invalid values fall back to the maximum and larger
+// values are clamped to it.
+func generationsParam(c *gin.Context) int {
+	generations, err := strconv.Atoi(c.Query("generations"))
+	if err != nil || generations < 1 || generations > genealogy.MaxPedigreeGenerations {
+		return genealogy.MaxPedigreeGenerations
+	}
+	return generations
+}
+
+// requirePersonPair reads the from and to query parameters. On failure it
+// writes the error response and returns false.
+func requirePersonPair(c *gin.Context) (string, string, bool) {
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "Both from and to person IDs are required",
+			},
+		})
+		return "", "", false
+	}
+	return from, to, true
+}
+
+// loadGraph builds the family graph and checks that every given person is
+// in it. On failure it writes the error response and returns false.
+func (h *GenealogyHandler) loadGraph(c *gin.Context, ids ...string) (*genealogy.Graph, bool) {
+	persons, err := h.repo.GetAllPersons(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to load persons")
+		return nil, false
+	}
+
+	links, err := h.repo.GetAllRelationships(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to load relationships")
+		return nil, false
+	}
+
+	g := genealogy.NewGraph(persons, links)
+	for _, id := range ids {
+		if !g.Has(id) {
+			respondPersonNotFound(c, id)
+			return nil, false
+		}
+	}
+	return g, true
+}
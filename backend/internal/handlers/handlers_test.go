@@ -0,0 +1,498 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+	"github.com/heemankverma/family_tree/backend/internal/snapshot"
+)
+
+// newTestRouter serves the person, relationship and upload routes from a
+// memory repository seeded with the example data. Admin auth is left out.
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repo := database.NewMemoryRepository()
+	repotest.Seed(t, context.Background(), repo, repotest.LoadFixtures(t))
+
+	persons := NewPersonHandler(repo)
+	relationships := NewRelationshipHandler(repo)
+	tree := NewTreeHandler(repo)
+	upload := NewUploadHandler(repo)
+
+	router := gin.New()
+	api := router.Group("/api")
+	api.GET("/person/:id", tree.GetPerson)
+	api.POST("/persons", persons.CreatePerson)
+	api.PUT("/person/:id", persons.UpdatePerson)
+	api.PATCH("/person/:id", persons.PatchPerson)
+	api.DELETE("/person/:id", persons.DeletePerson)
+	api.POST("/relationships", relationships.CreateRelationship)
+	api.PUT("/relationships/:type/:source/:target", relationships.UpdateRelationship)
+	api.DELETE("/relationships/:type/:source/:target", relationships.DeleteRelationship)
+	api.POST("/upload", upload.UploadCSV)
+	api.POST("/upload/relationships", upload.UploadRelationshipsCSV)
+	api.POST("/upload/gedcom", upload.UploadGEDCOM)
+	return router
+}
+
+// serve sends a request with an optional JSON body and returns the recorder
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// upload posts content as the "file" form field with the given filename
+func upload(t *testing.T, router *gin.Engine, path, filename, content string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	part.Write([]byte(content))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// errorCode returns the error code of an error response
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error response %q: %v", w.Body.String(), err)
+	}
+	return resp.Error.Code
+}
+
+func TestWriteErrors(t *testing.T) {
+	person := `{"id": "%s", "name": "Pat Doe", "gender": "Female", "is_alive": true, "birth_date": "1990-01-01"}`
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		status       int
+		code         string
+	}{
+		{"unknown person", http.MethodGet, "/api/person/nobody", "", http.StatusNotFound, "NOT_FOUND"},
+		{"create existing person", http.MethodPost, "/api/persons", fmt.Sprintf(person, "me-001"), http.StatusConflict, "ALREADY_EXISTS"},
+		{"create invalid person", http.MethodPost, "/api/persons", `{"id": "p-001", "gender": "Female"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"create with unknown field", http.MethodPost, "/api/persons", `{"id": "p-001", "nickname": "P"}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"update unknown person", http.MethodPut, "/api/person/nobody", fmt.Sprintf(person, "nobody"), http.StatusNotFound, "NOT_FOUND"},
+		{"update another id", http.MethodPut, "/api/person/me-001", fmt.Sprintf(person, "dad-001"), http.StatusBadRequest, "ID_MISMATCH"},
+		{"patch unknown person", http.MethodPatch, "/api/person/nobody", `{"profession": "Baker"}`, http.StatusNotFound, "NOT_FOUND"},
+		{"patch into invalid person", http.MethodPatch, "/api/person/me-001", `{"death_date": "2020-01-01"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"delete unknown person", http.MethodDelete, "/api/person/nobody", "", http.StatusNotFound, "NOT_FOUND"},
+		{"relationship to unknown person", http.MethodPost, "/api/relationships",
+			`{"source": "me-001", "target": "nobody", "relationship": "SIBLING"}`, http.StatusNotFound, "NOT_FOUND"},
+		{"existing relationship", http.MethodPost, "/api/relationships",
+			`{"source": "dad-001", "target": "me-001", "relationship": "parent_child"}`, http.StatusConflict, "ALREADY_EXISTS"},
+		{"ancestor cycle", http.MethodPost, "/api/relationships",
+			`{"source": "child-001", "target": "dad-001", "relationship": "PARENT_CHILD"}`, http.StatusConflict, "ANCESTOR_CYCLE"},
+		{"invalid relationship", http.MethodPost, "/api/relationships",
+			`{"source": "me-001", "target": "me-001", "relationship": "COUSIN"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"relationship with unknown field", http.MethodPost, "/api/relationships",
+			`{"source": "me-001", "target": "spouse-001", "relationship": "SPOUSE", "end_reson": "divorce"}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"update with unknown field", http.MethodPut, "/api/relationships/SPOUSE/me-001/spouse-001", `{"end_reson": "divorce"}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"update unknown relationship", http.MethodPut, "/api/relationships/SIBLING/me-001/child-001", `{}`, http.StatusNotFound, "NOT_FOUND"},
+		{"delete unknown relationship", http.MethodDelete, "/api/relationships/SPOUSE/me-001/dad-001", "", http.StatusNotFound, "NOT_FOUND"},
+		{"delete invalid relationship", http.MethodDelete, "/api/relationships/FRIEND/me-001/dad-001", "", http.StatusBadRequest, "VALIDATION_FAILED"},
+	}
+	router := newTestRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.method, tt.path, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d; want %d (%s)", w.Code, tt.status, w.Body.String())
+			}
+			if code := errorCode(t, w); code != tt.code {
+				t.Errorf("code = %s; want %s", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestRespondRepoError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("person: %w", database.ErrNotFound), http.StatusNotFound, "NOT_FOUND"},
+		{database.ErrAlreadyExists, http.StatusConflict, "ALREADY_EXISTS"},
+		{database.ErrCycle, http.StatusConflict, "ANCESTOR_CYCLE"},
+		{database.ErrInvalidInput, http.StatusBadRequest, "INVALID_INPUT"},
+		{database.ErrUnavailable, http.StatusServiceUnavailable, "DATABASE_UNAVAILABLE"},
+		{database.ErrTimeout, http.StatusGatewayTimeout, "DATABASE_TIMEOUT"},
+		{database.ErrNotSupported, http.StatusNotImplemented, "NOT_SUPPORTED"},
+		{fmt.Errorf("disk on fire"), http.StatusInternalServerError, "FETCH_ERROR"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		respondRepoError(c, tt.err, "FETCH_ERROR", "Failed to fetch")
+		if w.Code != tt.status {
+			t.Errorf("%v: status = %d; want %d", tt.err, w.Code, tt.status)
+		}
+		if code := errorCode(t, w); code != tt.code {
+			t.Errorf("%v: code = %s; want %s", tt.err, code, tt.code)
+		}
+	}
+}
+
+func TestUploadCSV(t *testing.T) {
+	router := newTestRouter(t)
+
+	persons := `id,name,gender,is_alive,birth_date,death_date
+me-001,Alex Smith,Male,TRUE,1985-09-25,
+p-001,Pat Doe,Female,TRUE,1990-01-01,
+p-002,Sam Doe,Male,FALSE,1950-01-01,2000-01-01
+`
+	statuses := func() []models.UpsertStatus {
+		t.Helper()
+		w := upload(t, router, "/api/upload", "persons.csv", persons)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d; want 200 (%s)", w.Code, w.Body.String())
+		}
+		var resp models.UploadResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		var got []models.UpsertStatus
+		for _, result := range resp.Results {
+			got = append(got, result.Status)
+		}
+		return got
+	}
+
+	// me-001 loses the fields the file leaves out
+	want := []models.UpsertStatus{models.UpsertUpdated, models.UpsertCreated, models.UpsertCreated}
+	if got := statuses(); !slices.Equal(got, want) {
+		t.Errorf("statuses = %v; want %v", got, want)
+	}
+	want = []models.UpsertStatus{models.UpsertUnchanged, models.UpsertUnchanged, models.UpsertUnchanged}
+	if got := statuses(); !slices.Equal(got, want) {
+		t.Errorf("statuses on reupload = %v; want %v", got, want)
+	}
+}
+
+func TestUploadDryRun(t *testing.T) {
+	router := newTestRouter(t)
+
+	persons := `id,name,gender,is_alive,birth_date,death_date
+me-001,Alex Smith,Male,TRUE,1985-09-25,
+p-001,Pat Doe,female,TRUE,1990-01-01,
+p-002,Sam Doe,Male,FALSE,01/02/1950,
+p-002,Sam Again,Male,TRUE,1950-01-01,2000-01-01
+`
+	w := upload(t, router, "/api/upload?dry_run=true", "persons.csv", persons)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200 (%s)", w.Code, w.Body.String())
+	}
+	var report models.ValidationReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	var got []string
+	for _, issue := range report.Issues {
+		got = append(got, fmt.Sprintf("%d:%s:%s", issue.Row, issue.Column, issue.Severity))
+	}
+	want := "1:id:warning 2:gender:error 3:birth_date:error 4:death_date:error 4:id:error"
+	if strings.Join(got, " ") != want {
+		t.Errorf("issues = %v; want %s", got, want)
+	}
+	if !report.DryRun || report.Valid || report.RowsParsed != 4 || report.Errors != 4 || report.Warnings != 1 {
+		t.Errorf("report = %+v; want a dry run of 4 rows with 4 errors and 1 warning", report)
+	}
+
+	// Nothing was written
+	if w := serve(router, http.MethodGet, "/api/person/p-001", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET p-001 after dry run: status = %d; want 404", w.Code)
+	}
+
+	// Without dry_run an invalid file is rejected as a whole
+	if w := upload(t, router, "/api/upload", "persons.csv", persons); w.Code != http.StatusBadRequest || errorCode(t, w) != "VALIDATION_FAILED" {
+		t.Errorf("upload: status = %d (%s); want 400 VALIDATION_FAILED", w.Code, w.Body.String())
+	}
+
+	missing := "id,name,gender\np-001,Pat Doe,Female\n"
+	if w := upload(t, router, "/api/upload?dry_run=true", "persons.csv", missing); w.Code != http.StatusBadRequest || errorCode(t, w) != "MISSING_COLUMN" {
+		t.Errorf("missing column: status = %d (%s); want 400 MISSING_COLUMN", w.Code, w.Body.String())
+	}
+
+	if w := upload(t, router, "/api/upload", "persons.txt", persons); w.Code != http.StatusBadRequest || errorCode(t, w) != "INVALID_FILE_TYPE" {
+		t.Errorf("text file: status = %d (%s); want 400 INVALID_FILE_TYPE", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadRelationships(t *testing.T) {
+	router := newTestRouter(t)
+
+	relationships := `type,person1_id,person2_id,start_date
+SIBLING,cousin-001,cousin-002,
+COUSIN,me-001,cousin-001,
+SPOUSE,me-001,nobody,2001-01-01
+PARENT_CHILD,child-001,me-001,
+`
+	w := upload(t, router, "/api/upload/relationships", "relationships.csv", relationships)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200 (%s)", w.Code, w.Body.String())
+	}
+	var resp models.UploadResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	var got []models.UpsertStatus
+	for _, result := range resp.Results {
+		got = append(got, result.Status)
+		if result.Status == models.UpsertFailed && result.Error == "" {
+			t.Errorf("row %d failed without an error", result.Row)
+		}
+	}
+	want := []models.UpsertStatus{models.UpsertCreated, models.UpsertFailed, models.UpsertFailed, models.UpsertFailed}
+	if fmt.Sprint(got) != fmt.Sprint(want) || resp.Created != 1 || resp.Failed != 3 {
+		t.Errorf("statuses = %v (created %d, failed %d); want %v", got, resp.Created, resp.Failed, want)
+	}
+}
+
+func TestUploadGEDCOMWithoutBirthDate(t *testing.T) {
+	router := newTestRouter(t)
+
+	ged := `0 HEAD
+1 CHAR UTF-8
+0 @I1@ INDI
+1 NAME Ram /Sharma/
+1 SEX M
+1 BIRT
+2 DATE 12 MAR 1950
+0 @I2@ INDI
+1 NAME Sita /Sharma/
+1 SEX F
+0 TRLR
+`
+	w := upload(t, router, "/api/upload/gedcom", "family.ged", ged)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200 (%s)", w.Code, w.Body.String())
+	}
+	var resp models.UploadResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	var columns []string
+	for _, w := range resp.Warnings {
+		columns = append(columns, w.Column)
+	}
+	if resp.Created != 2 || !slices.Equal(columns, []string{"INDI.BIRT.DATE", "INDI.DEAT"}) {
+		t.Fatalf("created %d with warnings %+v; want both persons, and warnings about the birth date and the presumed life", resp.Created, resp.Warnings)
+	}
+
+	// The unknown birth date does not stand in the way of later edits
+	id := resp.Results[1].ID
+	if w := serve(router, http.MethodPatch, "/api/person/"+id, `{"profession": "Weaver"}`); w.Code != http.StatusOK {
+		t.Errorf("PATCH %s: status = %d; want 200 (%s)", id, w.Code, w.Body.String())
+	}
+	if w := serve(router, http.MethodPatch, "/api/person/"+id, `{"birth_date": "1950"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("PATCH %s with an invalid birth date: status = %d; want 400", id, w.Code)
+	}
+}
+
+func TestUploadGEDCOMIDPrefix(t *testing.T) {
+	router := newTestRouter(t)
+
+	ged := "0 HEAD\n0 @I1@ INDI\n1 NAME Ram /Sharma/\n1 SEX M\n1 BIRT\n2 DATE 1950\n0 TRLR\n"
+	created := func(path string) []models.UpsertResult {
+		t.Helper()
+		w := upload(t, router, path, "family.ged", ged)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d; want 200 (%s)", path, w.Code, w.Body.String())
+		}
+		var resp models.UploadResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Results
+	}
+
+	// Files from two sources may use the same cross-reference IDs
+	if got := created("/api/upload/gedcom?id_prefix=smith"); got[0].ID != "smith-I1" || got[0].Status != models.UpsertCreated {
+		t.Errorf("results = %+v; want smith-I1 created", got)
+	}
+	if got := created("/api/upload/gedcom?id_prefix=jones"); got[0].ID != "jones-I1" || got[0].Status != models.UpsertCreated {
+		t.Errorf("results = %+v; want jones-I1 created", got)
+	}
+	if got := created("/api/upload/gedcom?id_prefix=smith"); got[0].Status != models.UpsertUnchanged {
+		t.Errorf("results on reimport = %+v; want smith-I1 unchanged", got)
+	}
+
+	w := upload(t, router, "/api/upload/gedcom?id_prefix=a/b", "family.ged", ged)
+	if w.Code != http.StatusBadRequest || errorCode(t, w) != "INVALID_REQUEST" {
+		t.Errorf("id_prefix=a/b: status = %d %s; want 400 INVALID_REQUEST", w.Code, w.Body.String())
+	}
+}
+
+// failingRepository is a repository whose writes fail on demand
+type failingRepository struct {
+	database.Repository
+	personsWritten int  // persons upserted before UpsertPersons fails, or -1
+	relationships  bool // whether UpsertRelationships fails
+	deletes        bool // whether DeletePerson fails
+}
+
+func (r *failingRepository) UpsertPersons(ctx context.Context, persons []models.Person) ([]models.UpsertStatus, error) {
+	if r.personsWritten < 0 {
+		return r.Repository.UpsertPersons(ctx, persons)
+	}
+	// Like a batched backend that fails after committing some batches
+	if _, err := r.Repository.UpsertPersons(ctx, persons[:r.personsWritten]); err != nil {
+		return nil, err
+	}
+	return nil, database.ErrUnavailable
+}
+
+func (r *failingRepository) UpsertRelationships(ctx context.Context, links []models.Link) ([]models.UpsertStatus, error) {
+	if r.relationships {
+		return nil, database.ErrUnavailable
+	}
+	return r.Repository.UpsertRelationships(ctx, links)
+}
+
+func (r *failingRepository) DeletePerson(ctx context.Context, id string) error {
+	if r.deletes {
+		return database.ErrUnavailable
+	}
+	return r.Repository.DeletePerson(ctx, id)
+}
+
+func TestUploadSnapshotRestore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	fx := repotest.LoadFixtures(t)
+
+	encode := func(persons []models.Person, links []models.Link) string {
+		data, err := json.Marshal(snapshot.New(persons, links, time.Now()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	full := encode(fx.Persons, fx.Links)
+	restore := func(repo database.Repository, content string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		t.Helper()
+		router := gin.New()
+		router.POST("/api/upload/snapshot", NewUploadHandler(repo).UploadSnapshot)
+		w := upload(t, router, "/api/upload/snapshot?mode=restore", "backup.json", content)
+		var resp struct {
+			Error struct {
+				Details map[string]interface{} `json:"details"`
+			} `json:"error"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp.Error.Details
+	}
+	count := func(repo database.Repository) (int, int) {
+		persons, _ := repo.GetAllPersons(ctx)
+		links, _ := repo.GetAllRelationships(ctx)
+		return len(persons), len(links)
+	}
+
+	t.Run("persons fail partway", func(t *testing.T) {
+		repo := &failingRepository{Repository: database.NewMemoryRepository(), personsWritten: 3}
+		w, details := restore(repo, full)
+		if w.Code != http.StatusServiceUnavailable || details["rolled_back"] != true {
+			t.Fatalf("status = %d %s; want 503 rolled back", w.Code, w.Body.String())
+		}
+		if persons, _ := count(repo); persons != 0 {
+			t.Errorf("%d persons left after a failed restore; want none", persons)
+		}
+	})
+
+	t.Run("relationships fail", func(t *testing.T) {
+		repo := &failingRepository{Repository: database.NewMemoryRepository(), personsWritten: -1, relationships: true}
+		if w, details := restore(repo, full); w.Code != http.StatusServiceUnavailable || details["rolled_back"] != true {
+			t.Fatalf("status = %d %s; want 503 rolled back", w.Code, w.Body.String())
+		}
+		if persons, _ := count(repo); persons != 0 {
+			t.Errorf("%d persons left after a failed restore; want none", persons)
+		}
+	})
+
+	t.Run("rollback fails", func(t *testing.T) {
+		repo := &failingRepository{Repository: database.NewMemoryRepository(), personsWritten: 2, deletes: true}
+		w, details := restore(repo, full)
+		incomplete, _ := details["rollback_incomplete"].([]interface{})
+		if w.Code != http.StatusServiceUnavailable || details["rolled_back"] != false || len(incomplete) != 2 {
+			t.Fatalf("status = %d %s; want 503 listing 2 persons not rolled back", w.Code, w.Body.String())
+		}
+
+		// The restore can be resumed from there
+		repo.personsWritten, repo.deletes = -1, false
+		if w, _ := restore(repo, full); w.Code != http.StatusOK {
+			t.Fatalf("resumed restore: status = %d; want 200 (%s)", w.Code, w.Body.String())
+		}
+		if persons, links := count(repo); persons != len(fx.Persons) || links != len(fx.Links) {
+			t.Errorf("%d persons and %d relationships after the resumed restore; want %d and %d", persons, links, len(fx.Persons), len(fx.Links))
+		}
+	})
+
+	t.Run("other data", func(t *testing.T) {
+		repo := database.NewMemoryRepository()
+		repotest.Seed(t, ctx, repo, fx)
+
+		// A relationship added since the backup
+		w, _ := restore(repo, encode(fx.Persons, fx.Links[1:]))
+		if w.Code != http.StatusConflict || errorCode(t, w) != "REPOSITORY_NOT_EMPTY" {
+			t.Errorf("restore over a newer relationship: status = %d %s; want 409 REPOSITORY_NOT_EMPTY", w.Code, w.Body.String())
+		}
+		// A person added since the backup
+		w, _ = restore(repo, encode(fx.Persons[1:], nil))
+		if w.Code != http.StatusConflict || errorCode(t, w) != "REPOSITORY_NOT_EMPTY" {
+			t.Errorf("restore over a newer person: status = %d %s; want 409 REPOSITORY_NOT_EMPTY", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		var parent models.Link
+		for _, link := range fx.Links {
+			if link.Relationship == models.RelationshipParentChild {
+				parent = link
+				break
+			}
+		}
+		reversed := models.Link{Relationship: parent.Relationship, Source: parent.Target, Target: parent.Source}
+		repo := database.NewMemoryRepository()
+		w, details := restore(repo, encode(fx.Persons, append(slices.Clone(fx.Links), reversed)))
+		if w.Code != http.StatusConflict || errorCode(t, w) != "ANCESTOR_CYCLE" || details["rolled_back"] != true {
+			t.Fatalf("status = %d %s; want 409 ANCESTOR_CYCLE rolled back", w.Code, w.Body.String())
+		}
+		if persons, links := count(repo); persons != 0 || links != 0 {
+			t.Errorf("%d persons and %d relationships left after a failed restore; want none", persons, links)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// PersonHandler handles the person write endpoints. Routes must be protected
+// with middleware.AdminAuth.
+type PersonHandler struct {
+	repo database.Repository
+}
+
+// NewPersonHandler creates a new person handler
+func NewPersonHandler(repo database.Repository) *PersonHandler {
+	return &PersonHandler{repo: repo}
+}
+
+// CreatePerson handles POST /api/persons
+func (h *PersonHandler) CreatePerson(c *gin.Context) {
+	var person models.Person
+	if !bindPerson(c, &person) {
+		return
+	}
+
+	if errs := validatePerson(person); len(errs) > 0 {
+		respondInvalidFields(c, errs)
+		return
+	}
+
+	if err := h.repo.CreatePerson(c.Request.Context(), person); err != nil {
+		respondRepoError(c, err, "CREATE_ERROR", "Failed to create person")
+		return
+	}
+
+	c.JSON(http.StatusCreated, person)
+}
+
+// UpdatePerson handles PUT /api/person/:id
+// The body replaces every field of the person.
+func (h *PersonHandler) UpdatePerson(c *gin.Context) {
+	id := c.Param("id")
+
+	var person models.Person
+	if !bindPerson(c, &person) {
+		return
+	}
+	if person.ID == "" {
+		person.ID = id
+	}
+	if !checkPathID(c, id, person.ID) {
+		return
+	}
+
+	if errs := validatePerson(person); len(errs) > 0 {
+		respondInvalidFields(c, errs)
+		return
+	}
+
+	if err := h.repo.UpdatePerson(c.Request.Context(), person); err != nil {
+		respondRepoError(c, err, "UPDATE_ERROR", "Failed to update person")
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// PatchPerson handles PATCH /api/person/:id
+// Only the fields present in the body are changed; "death_date": null clears
+// the death date.
+func (h *PersonHandler) PatchPerson(c *gin.Context) {
+	id := c.Param("id")
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	person, err := h.repo.GetPersonByID(c.Request.Context(), id)
+	if err != nil {
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to fetch person")
+		return
+	}
+
+	// A birth date left unknown by an import may stay unknown
+	validate := validatePerson
+	if person.BirthDate == "" {
+		validate = validateImportedPerson
+	}
+
+	// Decoding onto the stored person overwrites only the fields in the body
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(person); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	if !checkPathID(c, id, person.ID) {
+		return
+	}
+	normalizePerson(person)
+
+	if errs := validate(*person); len(errs) > 0 {
+		respondInvalidFields(c, errs)
+		return
+	}
+
+	if err := h.repo.UpdatePerson(c.Request.Context(), *person); err != nil {
+		respondRepoError(c, err, "UPDATE_ERROR", "Failed to update person")
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// DeletePerson handles DELETE /api/person/:id
+// The person's relationships are deleted with them.
+func (h *PersonHandler) DeletePerson(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeletePerson(c.Request.Context(), id); err != nil {
+		respondRepoError(c, err, "DELETE_ERROR", "Failed to delete person")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindPerson decodes a full person from the request body, rejecting unknown
+// fields. On failure it writes the error response and returns false.
+func bindPerson(c *gin.Context, person *models.Person) bool {
+	if !bindStrict(c, person) {
+		return false
+	}
+	normalizePerson(person)
+	return true
+}
+
+// bindStrict decodes the JSON request body into v, rejecting unknown fields
+// so that a misspelt field is not silently dropped. On failure it writes the
+// error response and returns false.
+func bindStrict(c *gin.Context, v any) bool {
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		respondInvalidBody(c, err)
+		return false
+	}
+	return true
+}
+
+// normalizePerson stores an absent aka list as empty rather than null
+func normalizePerson(person *models.Person) {
+	if person.Aka == nil {
+		person.Aka = []string{}
+	}
+}
+
+// checkPathID rejects a body whose id differs from the id in the URL. On
+// failure it writes the error response and returns false.
+func checkPathID(c *gin.Context, pathID, bodyID string) bool {
+	if bodyID != pathID {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "ID_MISMATCH",
+				Message: "Person ID in body does not match the URL",
+				Details: map[string]string{"url_id": pathID, "body_id": bodyID},
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// respondInvalidBody writes the error response for a body that is not valid JSON
+func respondInvalidBody(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: map[string]string{"error": err.Error()},
+		},
+	})
+}
@@ -62,15 +62,9 @@ func (h *QueryHandler) ExecuteQuery(c *gin.Context) {
 	}
 
 	// Execute the query
-	result, err := h.repo.ExecuteQuery(query)
+	result, err := h.repo.ExecuteQuery(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: models.ErrorDetail{
-				Code:    "QUERY_ERROR",
-				Message: "Failed to execute query",
-				Details: map[string]string{"error": err.Error()},
-			},
-		})
+		respondRepoError(c, err, "QUERY_ERROR", "Failed to execute query")
 		return
 	}
 
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// RelationshipHandler handles the relationship write endpoints. Routes must
+// be protected with middleware.AdminAuth.
+type RelationshipHandler struct {
+	repo database.Repository
+}
+
+// NewRelationshipHandler creates a new relationship handler
+func NewRelationshipHandler(repo database.Repository) *RelationshipHandler {
+	return &RelationshipHandler{repo: repo}
+}
+
+// relationshipDates is the request body for updating a relationship
+type relationshipDates struct {
+	StartDate *string `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+	EndReason *string `json:"end_reason"`
+}
+
+// CreateRelationship handles POST /api/relationships
+func (h *RelationshipHandler) CreateRelationship(c *gin.Context) {
+	var link models.Link
+	if !bindStrict(c, &link) {
+		return
+	}
+	link.Relationship = strings.ToUpper(link.Relationship)
+
+	if errs := validateLink(link); len(errs) > 0 {
+		respondInvalidFields(c, errs)
+		return
+	}
+
+	if err := h.repo.CreateRelationship(c.Request.Context(), link); err != nil {
+		respondRepoError(c, err, "CREATE_ERROR", "Failed to create relationship")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// UpdateRelationship handles PUT /api/relationships/:type/:source/:target
+// The body replaces both dates and the end reason; omitted fields are cleared.
+func (h *RelationshipHandler) UpdateRelationship(c *gin.Context) {
+	var dates relationshipDates
+	if !bindStrict(c, &dates) {
+		return
+	}
+
+	link := linkFromPath(c)
+	link.StartDate = dates.StartDate
+	link.EndDate = dates.EndDate
+	link.EndReason = dates.EndReason
+
+	if errs := validateLink(link); len(errs) > 0 {
+		respondInvalidFields(c, errs)
+		return
+	}
+
+	if err := h.repo.UpdateRelationship(c.Request.Context(), link); err != nil {
+		respondRepoError(c, err, "UPDATE_ERROR", "Failed to update relationship")
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// DeleteRelationship handles DELETE /api/relationships/:type/:source/:target
+func (h *RelationshipHandler) DeleteRelationship(c *gin.Context) {
+	link := linkFromPath(c)
+
+	if errs := validateLink(link); len(errs) > 0 {
+		respondInvalidFields(c, errs)
+		return
+	}
+
+	if err := h.repo.DeleteRelationship(c.Request.Context(), link.Relationship, link.Source, link.Target); err != nil {
+		respondRepoError(c, err, "DELETE_ERROR", "Failed to delete relationship")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// linkFromPath builds a link from the :type, :source and :target URL params
+func linkFromPath(c *gin.Context) models.Link {
+	return models.Link{
+		Source:       c.Param("source"),
+		Target:       c.Param("target"),
+		Relationship: strings.ToUpper(c.Param("type")),
+	}
+}
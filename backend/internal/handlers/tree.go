@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/heemankverma/family_tree/backend/internal/database"
 	"github.com/heemankverma/family_tree/backend/internal/models"
+	"github.com/heemankverma/family_tree/backend/internal/render"
 )
 
 // TreeHandler handles tree-related API endpoints
@@ -22,6 +25,49 @@ func NewTreeHandler(repo database.Repository) *TreeHandler {
 // GetTree handles GET /api/tree
 // Query params: centerNodeId (optional), depth (optional, default 2, max 3)
 func (h *TreeHandler) GetTree(c *gin.Context) {
+	treeData, _, ok := h.loadTree(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, treeData)
+}
+
+// GetTreeDOT handles GET /api/tree.dot, the tree of GetTree as a Graphviz graph
+// Query params: as for GetTree
+func (h *TreeHandler) GetTreeDOT(c *gin.Context) {
+	treeData, centerNodeID, ok := h.loadTree(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := render.DOT(&buf, treeData, centerNodeID); err != nil {
+		respondRepoError(c, err, "RENDER_ERROR", "Failed to render tree")
+		return
+	}
+	c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", buf.Bytes())
+}
+
+// GetTreeSVG handles GET /api/tree.svg, the tree of GetTree as an image
+// Query params: as for GetTree
+func (h *TreeHandler) GetTreeSVG(c *gin.Context) {
+	treeData, centerNodeID, ok := h.loadTree(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := render.SVG(&buf, treeData, centerNodeID); err != nil {
+		respondRepoError(c, err, "RENDER_ERROR", "Failed to render tree")
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", buf.Bytes())
+}
+
+// loadTree fetches the tree for the centerNodeId and depth query
+// parameters. On failure it writes the error response and returns false.
+func (h *TreeHandler) loadTree(c *gin.Context) (*models.TreeResponse, string, bool) {
 	centerNodeID := c.Query("centerNodeId")
 	depthStr := c.DefaultQuery("depth", "2")
 
@@ -33,19 +79,12 @@ func (h *TreeHandler) GetTree(c *gin.Context) {
 		depth = 3
 	}
 
-	treeData, err := h.repo.GetTreeData(centerNodeID, depth)
+	treeData, err := h.repo.GetTreeData(c.Request.Context(), centerNodeID, depth)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: models.ErrorDetail{
-				Code:    "TREE_FETCH_ERROR",
-				Message: "Failed to fetch tree data",
-				Details: map[string]string{"error": err.Error()},
-			},
-		})
-		return
+		respondRepoError(c, err, "TREE_FETCH_ERROR", "Failed to fetch tree data")
+		return nil, "", false
 	}
-
-	c.JSON(http.StatusOK, treeData)
+	return treeData, centerNodeID, true
 }
 
 // GetPerson handles GET /api/person/:id
@@ -61,15 +100,13 @@ func (h *TreeHandler) GetPerson(c *gin.Context) {
 		return
 	}
 
-	person, err := h.repo.GetPersonByID(id)
+	person, err := h.repo.GetPersonByID(c.Request.Context(), id)
+	if errors.Is(err, database.ErrNotFound) {
+		respondPersonNotFound(c, id)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: models.ErrorDetail{
-				Code:    "NOT_FOUND",
-				Message: "Person not found",
-				Details: map[string]string{"id": id},
-			},
-		})
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to fetch person")
 		return
 	}
 
@@ -89,15 +126,13 @@ func (h *TreeHandler) GetFamily(c *gin.Context) {
 		return
 	}
 
-	family, err := h.repo.GetImmediateFamily(id)
+	family, err := h.repo.GetImmediateFamily(c.Request.Context(), id)
+	if errors.Is(err, database.ErrNotFound) {
+		respondPersonNotFound(c, id)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: models.ErrorDetail{
-				Code:    "NOT_FOUND",
-				Message: "Person not found",
-				Details: map[string]string{"id": id},
-			},
-		})
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to fetch family")
 		return
 	}
 
@@ -106,15 +141,9 @@ func (h *TreeHandler) GetFamily(c *gin.Context) {
 
 // GetAllPersons handles GET /api/persons
 func (h *TreeHandler) GetAllPersons(c *gin.Context) {
-	persons, err := h.repo.GetAllPersons()
+	persons, err := h.repo.GetAllPersons(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: models.ErrorDetail{
-				Code:    "FETCH_ERROR",
-				Message: "Failed to fetch persons",
-				Details: map[string]string{"error": err.Error()},
-			},
-		})
+		respondRepoError(c, err, "FETCH_ERROR", "Failed to fetch persons")
 		return
 	}
 
@@ -123,3 +152,14 @@ func (h *TreeHandler) GetAllPersons(c *gin.Context) {
 		"count":   len(persons),
 	})
 }
+
+// respondPersonNotFound writes the 404 response for an unknown person ID
+func respondPersonNotFound(c *gin.Context, id string) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "NOT_FOUND",
+			Message: "Person not found",
+			Details: map[string]string{"id": id},
+		},
+	})
+}
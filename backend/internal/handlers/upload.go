@@ -1,154 +1,923 @@
 package handlers
 
 import (
-	"encoding/csv"
-	"io"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+	"github.com/heemankverma/family_tree/backend/internal/database"
+	"github.com/heemankverma/family_tree/backend/internal/gedcom"
 	"github.com/heemankverma/family_tree/backend/internal/models"
+	"github.com/heemankverma/family_tree/backend/internal/snapshot"
 )
 
-// UploadHandler handles CSV upload for bulk data ingestion
+// UploadHandler handles CSV, GEDCOM and snapshot upload for bulk data ingestion. Routes must be
+// protected with middleware.AdminAuth.
 type UploadHandler struct {
-	adminToken string
+	repo database.Repository
 }
 
 // NewUploadHandler creates a new upload handler
-func NewUploadHandler(adminToken string) *UploadHandler {
-	return &UploadHandler{adminToken: adminToken}
+func NewUploadHandler(repo database.Repository) *UploadHandler {
+	return &UploadHandler{repo: repo}
 }
 
 // UploadCSV handles POST /api/upload
+// Query params: dry_run (optional, "true" validates without writing)
 func (h *UploadHandler) UploadCSV(c *gin.Context) {
-	// Verify admin token
-	authHeader := c.GetHeader("Authorization")
-	expectedToken := "Bearer " + h.adminToken
-	if authHeader != expectedToken {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+	dryRun := c.Query("dry_run") == "true"
+
+	file, ok := uploadedCSV(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	// Parse CSV
+	rows, err := csvdata.ReadPersons(file)
+	if err != nil {
+		respondCSVError(c, err)
+		return
+	}
+
+	// Validate every row before writing anything
+	issues := personRowIssues(rows)
+	if dryRun {
+		ids := make([]string, len(rows))
+		for i, row := range rows {
+			ids[i] = row.Person.ID
+		}
+
+		existing, err := h.repo.ExistingPersonIDs(c.Request.Context(), ids)
+		if err != nil {
+			respondRepoError(c, err, "VALIDATION_ERROR", "Failed to look up persons")
+			return
+		}
+
+		for _, row := range rows {
+			if existing[row.Person.ID] {
+				issues = append(issues, models.ValidationIssue{
+					Row:      row.Row,
+					Column:   "id",
+					Value:    row.Person.ID,
+					Severity: models.SeverityWarning,
+					Message:  "id already exists in the database; the stored person will be updated",
+				})
+			}
+		}
+
+		c.JSON(http.StatusOK, newValidationReport(len(rows), dryRun, issues))
+		return
+	}
+
+	if report := newValidationReport(len(rows), dryRun, issues); !report.Valid {
+		respondValidationFailed(c, report)
+		return
+	}
+
+	// Persist persons
+	persons := make([]models.Person, len(rows))
+	for i, row := range rows {
+		persons[i] = row.Person
+	}
+
+	statuses, err := h.repo.UpsertPersons(c.Request.Context(), persons)
+	if err != nil {
+		respondRepoError(c, err, "IMPORT_ERROR", "Failed to import persons")
+		return
+	}
+
+	results := make([]models.UpsertResult, len(rows))
+	for i, row := range rows {
+		results[i] = models.UpsertResult{Row: row.Row, ID: row.Person.ID, Status: statuses[i]}
+	}
+
+	c.JSON(http.StatusOK, newUploadResponse(results))
+}
+
+// UploadRelationshipsCSV handles POST /api/upload/relationships
+// Query params: dry_run (optional, "true" validates without writing)
+func (h *UploadHandler) UploadRelationshipsCSV(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	file, ok := uploadedCSV(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	// Parse CSV
+	rows, err := csvdata.ReadRelationships(file)
+	if err != nil {
+		respondCSVError(c, err)
+		return
+	}
+
+	// Check that every referenced person exists
+	var ids []string
+	for _, row := range rows {
+		ids = append(ids, row.Link.Source, row.Link.Target)
+	}
+
+	existing, err := h.repo.ExistingPersonIDs(c.Request.Context(), ids)
+	if err != nil {
+		respondRepoError(c, err, "IMPORT_ERROR", "Failed to look up persons")
+		return
+	}
+
+	issues := relationshipRowIssues(rows, existing)
+	if dryRun {
+		c.JSON(http.StatusOK, newValidationReport(len(rows), dryRun, issues))
+		return
+	}
+
+	// Rows with errors are reported as failed; only valid rows are written
+	problems := make(map[int][]string)
+	for _, issue := range issues {
+		if issue.Severity == models.SeverityError {
+			problems[issue.Row] = append(problems[issue.Row], issue.Message)
+		}
+	}
+
+	results := make([]models.UpsertResult, len(rows))
+	var links []models.Link
+	var linkRows []int
+	for i, row := range rows {
+		link := row.Link
+		results[i] = linkResult(row.Row, link, "")
+
+		if messages, failed := problems[row.Row]; failed {
+			results[i].Status = models.UpsertFailed
+			results[i].Error = strings.Join(messages, "; ")
+			continue
+		}
+
+		links = append(links, link)
+		linkRows = append(linkRows, i)
+	}
+
+	if len(links) > 0 {
+		statuses, err := h.repo.UpsertRelationships(c.Request.Context(), links)
+		if err != nil {
+			respondRepoError(c, err, "IMPORT_ERROR", "Failed to import relationships")
+			return
+		}
+		for j, i := range linkRows {
+			results[i] = linkResult(results[i].Row, links[j], statuses[j])
+		}
+	}
+
+	c.JSON(http.StatusOK, newUploadResponse(results))
+}
+
+// gedcomIDPrefix is the form of the id_prefix of a GEDCOM upload
+var gedcomIDPrefix = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// UploadGEDCOM handles POST /api/upload/gedcom
+// Query params: dry_run (optional, "true" validates without writing),
+// id_prefix (optional, prepended to person IDs instead of one derived from
+// the file)
+func (h *UploadHandler) UploadGEDCOM(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	prefix := c.Query("id_prefix")
+	if prefix != "" && !gedcomIDPrefix.MatchString(prefix) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: models.ErrorDetail{
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid or missing admin token",
+				Code:    "INVALID_REQUEST",
+				Message: "id_prefix must be 1 to 32 letters, digits, dashes or underscores",
 			},
 		})
 		return
 	}
 
-	// Get the uploaded file
-	file, header, err := c.Request.FormFile("file")
+	file, ok := uploadedGEDCOM(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	imported, err := gedcom.Read(file, prefix)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: models.ErrorDetail{
-				Code:    "FILE_REQUIRED",
-				Message: "CSV file is required",
+				Code:    "GEDCOM_PARSE_ERROR",
+				Message: "Failed to read GEDCOM",
 				Details: map[string]string{"error": err.Error()},
 			},
 		})
 		return
 	}
-	defer file.Close()
 
-	// Validate file type
-	if header.Header.Get("Content-Type") != "text/csv" &&
-		header.Header.Get("Content-Type") != "application/vnd.ms-excel" {
-		// Check file extension as fallback
-		if len(header.Filename) < 4 || header.Filename[len(header.Filename)-4:] != ".csv" {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INVALID_FILE_TYPE",
-					Message: "Only CSV files are allowed",
-				},
-			})
+	// Like persons CSVs, the file is only imported if every record is valid
+	issues := gedcomRecordIssues(imported)
+	records := len(imported.Persons) + len(imported.Links)
+	if dryRun {
+		ids := make([]string, len(imported.Persons))
+		for i, record := range imported.Persons {
+			ids[i] = record.Person.ID
+		}
+
+		existing, err := h.repo.ExistingPersonIDs(c.Request.Context(), ids)
+		if err != nil {
+			respondRepoError(c, err, "VALIDATION_ERROR", "Failed to look up persons")
 			return
 		}
+
+		for _, record := range imported.Persons {
+			if existing[record.Person.ID] {
+				issues = append(issues, models.ValidationIssue{
+					Row:      record.Line,
+					Column:   "INDI",
+					Value:    record.Person.ID,
+					Severity: models.SeverityWarning,
+					Message:  "id already exists in the database; the stored person will be updated",
+				})
+			}
+		}
+
+		report := newValidationReport(records, dryRun, issues)
+		report.Unmapped = imported.Unmapped
+		c.JSON(http.StatusOK, report)
+		return
 	}
 
-	// Parse CSV
-	reader := csv.NewReader(file)
+	report := newValidationReport(records, dryRun, issues)
+	if !report.Valid {
+		report.Unmapped = imported.Unmapped
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "VALIDATION_FAILED",
+				Message: "GEDCOM contains invalid records; nothing was imported",
+				Details: report,
+			},
+		})
+		return
+	}
 
-	// Read header row
-	headers, err := reader.Read()
+	persons := make([]models.Person, len(imported.Persons))
+	for i, record := range imported.Persons {
+		persons[i] = record.Person
+	}
+	personStatuses, err := h.repo.UpsertPersons(c.Request.Context(), persons)
 	if err != nil {
+		respondRepoError(c, err, "IMPORT_ERROR", "Failed to import persons")
+		return
+	}
+
+	links := make([]models.Link, len(imported.Links))
+	for i, record := range imported.Links {
+		links[i] = record.Link
+	}
+	var linkStatuses []models.UpsertStatus
+	if len(links) > 0 {
+		linkStatuses, err = h.repo.UpsertRelationships(c.Request.Context(), links)
+		if err != nil {
+			respondRepoError(c, err, "IMPORT_ERROR", "Failed to import relationships")
+			return
+		}
+	}
+
+	results := make([]models.UpsertResult, 0, records)
+	for i, record := range imported.Persons {
+		results = append(results, models.UpsertResult{Row: record.Line, ID: record.Person.ID, Status: personStatuses[i]})
+	}
+	for i, record := range imported.Links {
+		results = append(results, linkResult(record.Line, record.Link, linkStatuses[i]))
+	}
+
+	response := newUploadResponse(results)
+	response.Message = "GEDCOM imported successfully"
+	response.Warnings = report.Issues
+	response.Unmapped = imported.Unmapped
+	c.JSON(http.StatusOK, response)
+}
+
+// Snapshot import modes
+const (
+	snapshotRestore = "restore" // only into an empty repository, or to resume one
+	snapshotMerge   = "merge"   // create or update alongside stored data
+)
+
+// UploadSnapshot handles POST /api/upload/snapshot
+// Query params: mode (optional, "restore" or "merge", default "merge"),
+// dry_run (optional, "true" validates without writing)
+func (h *UploadHandler) UploadSnapshot(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	mode := c.DefaultQuery("mode", snapshotMerge)
+	if mode != snapshotRestore && mode != snapshotMerge {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: models.ErrorDetail{
-				Code:    "CSV_PARSE_ERROR",
-				Message: "Failed to read CSV headers",
-				Details: map[string]string{"error": err.Error()},
+				Code:    "INVALID_REQUEST",
+				Message: "Unsupported mode",
+				Details: map[string]interface{}{"supported_modes": []string{snapshotRestore, snapshotMerge}},
 			},
 		})
 		return
 	}
 
-	// Validate required columns
-	requiredColumns := []string{"id", "name", "gender", "birth_date"}
-	columnIndex := make(map[string]int)
-	for i, h := range headers {
-		columnIndex[h] = i
+	file, ok := uploadedFile(c, "JSON", []string{"application/json"}, ".json")
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	s, err := snapshot.Read(file)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
 	}
 
-	for _, col := range requiredColumns {
-		if _, exists := columnIndex[col]; !exists {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+	// Persons stored before a restore, which a failed restore keeps
+	var before map[string]bool
+	if mode == snapshotRestore {
+		stored, err := h.repo.GetAllPersons(c.Request.Context())
+		if err != nil {
+			respondRepoError(c, err, "IMPORT_ERROR", "Failed to look up persons")
+			return
+		}
+		storedLinks, err := h.repo.GetAllRelationships(c.Request.Context())
+		if err != nil {
+			respondRepoError(c, err, "IMPORT_ERROR", "Failed to look up relationships")
+			return
+		}
+		// A repository holding only part of the snapshot is taken to be an
+		// interrupted restore of it, which may be completed
+		if !partOf(stored, storedLinks, s) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
 				Error: models.ErrorDetail{
-					Code:    "MISSING_COLUMN",
-					Message: "Required column missing: " + col,
-					Details: map[string]interface{}{
-						"required_columns": requiredColumns,
-						"found_columns":    headers,
-					},
+					Code:    "REPOSITORY_NOT_EMPTY",
+					Message: "A snapshot can only be restored into an empty repository, or one holding only its persons and relationships; use mode=merge to import alongside stored data",
+					Details: map[string]int{"stored_persons": len(stored), "stored_relationships": len(storedLinks)},
 				},
 			})
 			return
 		}
+		before = make(map[string]bool, len(stored))
+		for _, person := range stored {
+			before[person.ID] = true
+		}
+	}
+
+	// Relationships may point to stored persons when merging
+	var ids []string
+	for _, person := range s.Persons {
+		ids = append(ids, person.ID)
+	}
+	for _, link := range s.Relationships {
+		ids = append(ids, link.Source, link.Target)
+	}
+	existing, err := h.repo.ExistingPersonIDs(c.Request.Context(), ids)
+	if err != nil {
+		respondRepoError(c, err, "IMPORT_ERROR", "Failed to look up persons")
+		return
 	}
 
-	// Parse rows
-	var records []map[string]string
-	rowNum := 1
-	for {
-		row, err := reader.Read()
-		if err == io.EOF {
-			break
+	// Like GEDCOM, the snapshot is only imported if every record is valid
+	issues := snapshotIssues(s, existing)
+	records := len(s.Persons) + len(s.Relationships)
+	if dryRun {
+		for i, person := range s.Persons {
+			if existing[person.ID] {
+				issues = append(issues, models.ValidationIssue{
+					Row:      i + 1,
+					Column:   "persons.id",
+					Value:    person.ID,
+					Severity: models.SeverityWarning,
+					Message:  "id already exists in the database; the stored person will be updated",
+				})
+			}
 		}
+		c.JSON(http.StatusOK, newValidationReport(records, dryRun, issues))
+		return
+	}
+
+	if report := newValidationReport(records, dryRun, issues); !report.Valid {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "VALIDATION_FAILED",
+				Message: "Snapshot contains invalid records; nothing was imported",
+				Details: report,
+			},
+		})
+		return
+	}
+
+	results := make([]models.UpsertResult, 0, records)
+	if len(s.Persons) > 0 {
+		statuses, err := h.repo.UpsertPersons(c.Request.Context(), s.Persons)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "CSV_PARSE_ERROR",
-					Message: "Failed to parse CSV row",
-					Details: map[string]interface{}{
-						"row":   rowNum,
-						"error": err.Error(),
-					},
+			h.failSnapshot(c, mode, s, before, err, "Failed to import persons")
+			return
+		}
+		for i, person := range s.Persons {
+			results = append(results, models.UpsertResult{Row: i + 1, ID: person.ID, Status: statuses[i]})
+		}
+	}
+	if len(s.Relationships) > 0 {
+		statuses, err := h.repo.UpsertRelationships(c.Request.Context(), s.Relationships)
+		if err != nil {
+			h.failSnapshot(c, mode, s, before, err, "Failed to import relationships")
+			return
+		}
+		for i, link := range s.Relationships {
+			result := linkResult(i+1, link, statuses[i])
+			// A restore that leaves out part of the tree is no restore
+			if mode == snapshotRestore && result.Status == models.UpsertFailed {
+				err := fmt.Errorf("relationship %d, %s as parent of %s: %w", result.Row, link.Source, link.Target, database.ErrCycle)
+				h.failSnapshot(c, mode, s, before, err, "Failed to import relationships")
+				return
+			}
+			results = append(results, result)
+		}
+	}
+
+	response := newUploadResponse(results)
+	response.Message = "Snapshot restored successfully"
+	if mode == snapshotMerge {
+		response.Message = "Snapshot merged successfully"
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// partOf reports whether every stored person and relationship is in the
+// snapshot
+func partOf(stored []models.Person, storedLinks []models.Link, s *models.Snapshot) bool {
+	ids := make(map[string]bool, len(s.Persons))
+	for _, person := range s.Persons {
+		ids[person.ID] = true
+	}
+	for _, person := range stored {
+		if !ids[person.ID] {
+			return false
+		}
+	}
+
+	keys := make(map[string]bool, len(s.Relationships))
+	for _, link := range s.Relationships {
+		keys[database.LinkKey(link)] = true
+	}
+	for _, link := range storedLinks {
+		if !keys[database.LinkKey(link)] {
+			return false
+		}
+	}
+	return true
+}
+
+// failSnapshot writes the error response for a snapshot import that failed
+// while writing. A failed restore is first rolled back by deleting the
+// snapshot's persons that were not stored before it, along with their
+// relationships: the backends have no transaction spanning both upserts,
+// and may have written some persons before failing. Persons that could not
+// be deleted are logged and listed as rollback_incomplete; restoring the
+// snapshot again resumes the restore instead.
+func (h *UploadHandler) failSnapshot(c *gin.Context, mode string, s *models.Snapshot, before map[string]bool, err error, message string) {
+	status, response := repoErrorResponse(err, "IMPORT_ERROR", message)
+	if mode != snapshotRestore {
+		c.JSON(status, response)
+		return
+	}
+
+	// The request may have been cancelled, which is why the import failed
+	ctx := context.WithoutCancel(c.Request.Context())
+	ids := make([]string, len(s.Persons))
+	for i, person := range s.Persons {
+		ids[i] = person.ID
+	}
+	incomplete := []string{}
+	existing, lookupErr := h.repo.ExistingPersonIDs(ctx, ids)
+	if lookupErr != nil {
+		log.Printf("snapshot restore rollback: looking up persons: %v", lookupErr)
+		incomplete = ids
+	}
+	for _, id := range ids {
+		if lookupErr != nil || !existing[id] || before[id] {
+			continue
+		}
+		if err := h.repo.DeletePerson(ctx, id); err != nil {
+			log.Printf("snapshot restore rollback: deleting person %s: %v", id, err)
+			incomplete = append(incomplete, id)
+		}
+	}
+
+	details := map[string]interface{}{"error": err.Error(), "rolled_back": len(incomplete) == 0}
+	if len(incomplete) > 0 {
+		details["rollback_incomplete"] = incomplete
+	}
+	response.Error.Details = details
+	c.JSON(status, response)
+}
+
+// uploadedCSV returns the CSV file from the "file" form field. On failure it
+// writes the error response and returns false.
+func uploadedCSV(c *gin.Context) (multipart.File, bool) {
+	return uploadedFile(c, "CSV", []string{"text/csv", "application/vnd.ms-excel"}, ".csv")
+}
+
+// uploadedGEDCOM returns the GEDCOM file from the "file" form field. On
+// failure it writes the error response and returns false.
+func uploadedGEDCOM(c *gin.Context) (multipart.File, bool) {
+	return uploadedFile(c, "GEDCOM", []string{"application/x-gedcom", "text/vnd.familysearch.gedcom"}, ".ged")
+}
+
+// uploadedFile returns the file from the "file" form field if it has one of
+// the content types or, as a fallback, the extension. On failure it writes
+// the error response and returns false.
+func uploadedFile(c *gin.Context, kind string, contentTypes []string, extension string) (multipart.File, bool) {
+	// Get the uploaded file
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "FILE_REQUIRED",
+				Message: kind + " file is required",
+				Details: map[string]string{"error": err.Error()},
+			},
+		})
+		return nil, false
+	}
+
+	// Validate file type, checking the extension as fallback
+	if !slices.Contains(contentTypes, header.Header.Get("Content-Type")) &&
+		!strings.HasSuffix(strings.ToLower(header.Filename), extension) {
+		file.Close()
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_FILE_TYPE",
+				Message: "Only " + kind + " files are allowed",
+			},
+		})
+		return nil, false
+	}
+
+	return file, true
+}
+
+// respondCSVError writes the error response for a CSV that could not be read
+func respondCSVError(c *gin.Context, err error) {
+	var missingErr *csvdata.MissingColumnError
+	if errors.As(err, &missingErr) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "MISSING_COLUMN",
+				Message: "Required column missing: " + missingErr.Column,
+				Details: map[string]interface{}{
+					"required_columns": missingErr.Required,
+					"found_columns":    missingErr.Found,
+				},
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "CSV_PARSE_ERROR",
+			Message: "Failed to read CSV",
+			Details: map[string]string{"error": err.Error()},
+		},
+	})
+}
+
+// respondSnapshotError writes the error response for a snapshot that could
+// not be read or failed verification
+func respondSnapshotError(c *gin.Context, err error) {
+	var versionErr *snapshot.VersionError
+	var checksumErr *snapshot.ChecksumError
+	switch {
+	case errors.As(err, &versionErr):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "UNSUPPORTED_SCHEMA_VERSION",
+				Message: "Unsupported snapshot schema version",
+				Details: map[string]interface{}{
+					"schema_version":     versionErr.Version,
+					"supported_versions": fmt.Sprintf("1 to %d", snapshot.SchemaVersion),
 				},
+			},
+		})
+	case errors.As(err, &checksumErr):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "CHECKSUM_MISMATCH",
+				Message: "Snapshot " + checksumErr.Section + " do not match their checksum; the file is damaged or was edited",
+				Details: map[string]string{
+					"section":  checksumErr.Section,
+					"expected": checksumErr.Expected,
+					"actual":   checksumErr.Actual,
+				},
+			},
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SNAPSHOT_PARSE_ERROR",
+				Message: "Failed to read snapshot",
+				Details: map[string]string{"error": err.Error()},
+			},
+		})
+	}
+}
+
+// respondValidationFailed writes the error response for an upload rejected
+// because of invalid rows
+func respondValidationFailed(c *gin.Context, report models.ValidationReport) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "VALIDATION_FAILED",
+			Message: "CSV contains invalid rows; nothing was imported",
+			Details: report,
+		},
+	})
+}
+
+// linkResult reports the outcome of writing a relationship. The repository
+// only fails links that would make someone their own ancestor.
+func linkResult(row int, link models.Link, status models.UpsertStatus) models.UpsertResult {
+	result := models.UpsertResult{
+		Row:    row,
+		ID:     link.Relationship + ":" + link.Source + ":" + link.Target,
+		Status: status,
+	}
+	if status == models.UpsertFailed {
+		result.Error = fmt.Sprintf("%s as parent of %s %v", link.Source, link.Target, database.ErrCycle)
+	}
+	return result
+}
+
+// newUploadResponse tallies per-row results into an upload response
+func newUploadResponse(results []models.UpsertResult) models.UploadResponse {
+	response := models.UploadResponse{
+		Message:    "CSV imported successfully",
+		RowsParsed: len(results),
+		Results:    results,
+	}
+	for _, result := range results {
+		switch result.Status {
+		case models.UpsertCreated:
+			response.Created++
+		case models.UpsertUpdated:
+			response.Updated++
+		case models.UpsertUnchanged:
+			response.Unchanged++
+		case models.UpsertFailed:
+			response.Failed++
+		}
+	}
+	return response
+}
+
+// newValidationReport tallies validation issues into a report
+func newValidationReport(rowsParsed int, dryRun bool, issues []models.ValidationIssue) models.ValidationReport {
+	report := models.ValidationReport{
+		DryRun:     dryRun,
+		RowsParsed: rowsParsed,
+		Issues:     make([]models.ValidationIssue, 0, len(issues)),
+	}
+	for _, issue := range issues {
+		switch issue.Severity {
+		case models.SeverityError:
+			report.Errors++
+		case models.SeverityWarning:
+			report.Warnings++
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+	report.Valid = report.Errors == 0
+
+	slices.SortStableFunc(report.Issues, func(a, b models.ValidationIssue) int {
+		return a.Row - b.Row
+	})
+	return report
+}
+
+// personRowIssues validates every row of a persons CSV, including IDs that
+// appear more than once in the file
+func personRowIssues(rows []csvdata.PersonRow) []models.ValidationIssue {
+	var issues []models.ValidationIssue
+	firstSeen := make(map[string]int)
+
+	for _, row := range rows {
+		if row.Err != nil {
+			issues = append(issues, models.ValidationIssue{
+				Row:      row.Row,
+				Severity: models.SeverityError,
+				Message:  row.Err.Error(),
+			})
+			continue
+		}
+
+		if value := strings.TrimSpace(row.Record["is_alive"]); value != "" && !csvdata.IsBool(value) {
+			issues = append(issues, models.ValidationIssue{
+				Row:      row.Row,
+				Column:   "is_alive",
+				Value:    value,
+				Severity: models.SeverityError,
+				Message:  fmt.Sprintf("invalid is_alive %q (expected TRUE or FALSE)", value),
+			})
+		}
+
+		for _, fe := range validatePerson(row.Person) {
+			issues = append(issues, models.ValidationIssue{
+				Row:      row.Row,
+				Column:   fe.Field,
+				Value:    row.Record[fe.Field],
+				Severity: models.SeverityError,
+				Message:  fe.Message,
+			})
+		}
+
+		id := row.Person.ID
+		if id == "" {
+			continue
+		}
+		if first, seen := firstSeen[id]; seen {
+			issues = append(issues, models.ValidationIssue{
+				Row:      row.Row,
+				Column:   "id",
+				Value:    id,
+				Severity: models.SeverityError,
+				Message:  fmt.Sprintf("duplicate id (first used in row %d)", first),
+			})
+		} else {
+			firstSeen[id] = row.Row
+		}
+	}
+
+	return issues
+}
+
+// linkColumns maps models.Link JSON fields to relationships CSV columns
+var linkColumns = map[string]string{
+	"relationship": "type",
+	"source":       "person1_id",
+	"target":       "person2_id",
+	"start_date":   "start_date",
+	"end_date":     "end_date",
+	"end_reason":   "end_reason",
+}
+
+// relationshipRowIssues validates every row of a relationships CSV, including
+// references to persons that do not exist
+func relationshipRowIssues(rows []csvdata.RelationshipRow, existing map[string]bool) []models.ValidationIssue {
+	var issues []models.ValidationIssue
+
+	for _, row := range rows {
+		if row.Err != nil {
+			issues = append(issues, models.ValidationIssue{
+				Row:      row.Row,
+				Severity: models.SeverityError,
+				Message:  row.Err.Error(),
+			})
+			continue
+		}
+
+		for _, fe := range validateLink(row.Link) {
+			column := linkColumns[fe.Field]
+			issues = append(issues, models.ValidationIssue{
+				Row:      row.Row,
+				Column:   column,
+				Value:    row.Record[column],
+				Severity: models.SeverityError,
+				Message:  fe.Message,
 			})
-			return
 		}
 
-		record := make(map[string]string)
-		for i, value := range row {
-			if i < len(headers) {
-				record[headers[i]] = value
+		for _, ref := range []struct{ column, id string }{
+			{"person1_id", row.Link.Source},
+			{"person2_id", row.Link.Target},
+		} {
+			if ref.id != "" && !existing[ref.id] {
+				issues = append(issues, models.ValidationIssue{
+					Row:      row.Row,
+					Column:   ref.column,
+					Value:    ref.id,
+					Severity: models.SeverityError,
+					Message:  "person not found: " + ref.id,
+				})
 			}
 		}
-		records = append(records, record)
-		rowNum++
 	}
 
-	// In a real implementation, this would batch MERGE commands to Neo4j
-	// For now, return success with parsed data summary
-	c.JSON(http.StatusOK, gin.H{
-		"message":      "CSV parsed successfully",
-		"rows_parsed":  len(records),
-		"columns":      headers,
-		"preview":      records[:min(5, len(records))],
-		"note":         "In production, this would create/update Person nodes in Neo4j",
-		"mock_mode":    true,
-	})
+	return issues
+}
+
+// gedcomTags maps models.Person and models.Link JSON fields to the GEDCOM
+// tags they are read from
+var gedcomTags = map[string]string{
+	"id":           "INDI",
+	"name":         "INDI.NAME",
+	"gender":       "INDI.SEX",
+	"birth_date":   "INDI.BIRT.DATE",
+	"death_date":   "INDI.DEAT.DATE",
+	"relationship": "FAM",
+	"source":       "FAM",
+	"target":       "FAM",
+	"start_date":   "FAM.MARR.DATE",
+	"end_date":     "FAM.DIV.DATE",
+	"end_reason":   "FAM.DIV",
+}
+
+// gedcomRecordIssues validates the persons and relationships of an imported
+// GEDCOM file on top of the problems found while mapping it
+func gedcomRecordIssues(imported *gedcom.Import) []models.ValidationIssue {
+	issues := slices.Clone(imported.Issues)
+
+	for _, record := range imported.Persons {
+		for _, fe := range validateImportedPerson(record.Person) {
+			issues = append(issues, models.ValidationIssue{
+				Row:      record.Line,
+				Column:   gedcomTags[fe.Field],
+				Value:    record.Person.ID,
+				Severity: models.SeverityError,
+				Message:  fe.Message,
+			})
+		}
+	}
+
+	for _, record := range imported.Links {
+		for _, fe := range validateLink(record.Link) {
+			issues = append(issues, models.ValidationIssue{
+				Row:      record.Line,
+				Column:   gedcomTags[fe.Field],
+				Severity: models.SeverityError,
+				Message:  fe.Message,
+			})
+		}
+	}
+
+	return issues
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// snapshotIssues validates the persons and relationships of a snapshot.
+// Row is the 1-based position in the persons or relationships array and
+// Column the section and field. Relationships may point to persons in the
+// snapshot or to existing ones.
+func snapshotIssues(s *models.Snapshot, existing map[string]bool) []models.ValidationIssue {
+	var issues []models.ValidationIssue
+	included := make(map[string]bool, len(s.Persons))
+	firstSeen := make(map[string]int)
+
+	for i, person := range s.Persons {
+		for _, fe := range validateImportedPerson(person) {
+			issues = append(issues, models.ValidationIssue{
+				Row:      i + 1,
+				Column:   "persons." + fe.Field,
+				Value:    person.ID,
+				Severity: models.SeverityError,
+				Message:  fe.Message,
+			})
+		}
+
+		included[person.ID] = true
+		if first, seen := firstSeen[person.ID]; seen {
+			issues = append(issues, models.ValidationIssue{
+				Row:      i + 1,
+				Column:   "persons.id",
+				Value:    person.ID,
+				Severity: models.SeverityError,
+				Message:  fmt.Sprintf("duplicate id (first used in person %d)", first),
+			})
+		} else {
+			firstSeen[person.ID] = i + 1
+		}
 	}
-	return b
+
+	for i, link := range s.Relationships {
+		for _, fe := range validateLink(link) {
+			issues = append(issues, models.ValidationIssue{
+				Row:      i + 1,
+				Column:   "relationships." + fe.Field,
+				Severity: models.SeverityError,
+				Message:  fe.Message,
+			})
+		}
+
+		for _, ref := range []struct{ field, id string }{
+			{"source", link.Source},
+			{"target", link.Target},
+		} {
+			if ref.id != "" && !included[ref.id] && !existing[ref.id] {
+				issues = append(issues, models.ValidationIssue{
+					Row:      i + 1,
+					Column:   "relationships." + ref.field,
+					Value:    ref.id,
+					Severity: models.SeverityError,
+					Message:  "person not found: " + ref.id,
+				})
+			}
+		}
+	}
+
+	return issues
 }
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// Genders accepted for models.Person.Gender
+var validGenders = []string{"Male", "Female", "Other"}
+
+// fieldError describes an invalid field of a model
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validatePerson checks the fields of a person and returns every problem found
+func validatePerson(p models.Person) []fieldError {
+	var errs []fieldError
+
+	if strings.TrimSpace(p.ID) == "" {
+		errs = append(errs, fieldError{"id", "id is required"})
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		errs = append(errs, fieldError{"name", "name is required"})
+	}
+
+	if p.Gender == "" {
+		errs = append(errs, fieldError{"gender", "gender is required"})
+	} else if !slices.Contains(validGenders, p.Gender) {
+		errs = append(errs, fieldError{"gender", fmt.Sprintf("invalid gender %q (expected one of %s)",
+			p.Gender, strings.Join(validGenders, ", "))})
+	}
+
+	if p.BirthDate == "" {
+		errs = append(errs, fieldError{"birth_date", "birth_date is required"})
+	} else if !isISODate(p.BirthDate) {
+		errs = append(errs, fieldError{"birth_date", fmt.Sprintf("invalid birth_date %q (expected YYYY-MM-DD)", p.BirthDate)})
+	}
+
+	if p.DeathDate != nil {
+		switch {
+		case !isISODate(*p.DeathDate):
+			errs = append(errs, fieldError{"death_date", fmt.Sprintf("invalid death_date %q (expected YYYY-MM-DD)", *p.DeathDate)})
+		case p.IsAlive:
+			errs = append(errs, fieldError{"death_date", "death_date must be empty when is_alive is true"})
+		case isISODate(p.BirthDate) && *p.DeathDate < p.BirthDate:
+			errs = append(errs, fieldError{"death_date", "death_date is before birth_date"})
+		}
+	}
+
+	return errs
+}
+
+// validateImportedPerson is validatePerson for persons imported from GEDCOM
+// files or snapshots, or already stored from them, whose birth date may be
+// unknown: an empty birth_date is allowed
+func validateImportedPerson(p models.Person) []fieldError {
+	errs := validatePerson(p)
+	if p.BirthDate == "" {
+		errs = slices.DeleteFunc(errs, func(fe fieldError) bool { return fe.Field == "birth_date" })
+	}
+	return errs
+}
+
+// isISODate reports whether s is a calendar date in YYYY-MM-DD format
+func isISODate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// validateLink checks the fields of a relationship and returns every problem found
+func validateLink(link models.Link) []fieldError {
+	var errs []fieldError
+
+	if !slices.Contains(models.RelationshipTypes, link.Relationship) {
+		errs = append(errs, fieldError{"relationship", fmt.Sprintf("invalid relationship type %q (expected one of %s)",
+			link.Relationship, strings.Join(models.RelationshipTypes, ", "))})
+	}
+	if link.Source == "" {
+		errs = append(errs, fieldError{"source", "source person ID is required"})
+	}
+	if link.Target == "" {
+		errs = append(errs, fieldError{"target", "target person ID is required"})
+	}
+	if link.Source != "" && link.Source == link.Target {
+		errs = append(errs, fieldError{"target", "a person cannot be related to themselves"})
+	}
+	if link.StartDate != nil && !isISODate(*link.StartDate) {
+		errs = append(errs, fieldError{"start_date", fmt.Sprintf("invalid start_date %q (expected YYYY-MM-DD)", *link.StartDate)})
+	}
+	if link.EndDate != nil && !isISODate(*link.EndDate) {
+		errs = append(errs, fieldError{"end_date", fmt.Sprintf("invalid end_date %q (expected YYYY-MM-DD)", *link.EndDate)})
+	} else if link.StartDate != nil && link.EndDate != nil && *link.EndDate < *link.StartDate {
+		errs = append(errs, fieldError{"end_date", "end_date is before start_date"})
+	}
+	if link.EndReason != nil {
+		switch {
+		case link.Relationship != models.RelationshipSpouse:
+			errs = append(errs, fieldError{"end_reason", "end_reason is only allowed on SPOUSE relationships"})
+		case !slices.Contains(models.EndReasons, *link.EndReason):
+			errs = append(errs, fieldError{"end_reason", fmt.Sprintf("invalid end_reason %q (expected one of %s)",
+				*link.EndReason, strings.Join(models.EndReasons, ", "))})
+		}
+	}
+
+	return errs
+}
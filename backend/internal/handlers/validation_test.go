@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/csvdata"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// fields returns the field of every error, in order
+func fields(errs []fieldError) []string {
+	names := make([]string, len(errs))
+	for i, fe := range errs {
+		names[i] = fe.Field
+	}
+	return names
+}
+
+func TestValidatePerson(t *testing.T) {
+	date := func(s string) *string { return &s }
+	valid := models.Person{ID: "p-001", Name: "Pat Doe", Gender: "Female", BirthDate: "1950-02-28", DeathDate: date("2020-01-01")}
+
+	tests := []struct {
+		name   string
+		change func(p *models.Person)
+		want   []string
+	}{
+		{"valid", func(p *models.Person) {}, nil},
+		{"living without death date", func(p *models.Person) { p.IsAlive, p.DeathDate = true, nil }, nil},
+		{"missing id and name", func(p *models.Person) { p.ID, p.Name = " ", "" }, []string{"id", "name"}},
+		{"missing gender", func(p *models.Person) { p.Gender = "" }, []string{"gender"}},
+		{"unknown gender", func(p *models.Person) { p.Gender = "male" }, []string{"gender"}},
+		{"missing birth date", func(p *models.Person) { p.BirthDate = "" }, []string{"birth_date"}},
+		{"birth date not ISO", func(p *models.Person) { p.BirthDate = "28/02/1950" }, []string{"birth_date"}},
+		{"birth date not a day", func(p *models.Person) { p.BirthDate = "1950-02-30" }, []string{"birth_date"}},
+		{"death date not ISO", func(p *models.Person) { p.DeathDate = date("2020") }, []string{"death_date"}},
+		{"death date while alive", func(p *models.Person) { p.IsAlive = true }, []string{"death_date"}},
+		{"death before birth", func(p *models.Person) { p.DeathDate = date("1949-12-31") }, []string{"death_date"}},
+		{"every field", func(p *models.Person) { *p = models.Person{Gender: "?", BirthDate: "x", DeathDate: date("y")} },
+			[]string{"id", "name", "gender", "birth_date", "death_date"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.change(&p)
+			if got := fields(validatePerson(p)); !slices.Equal(got, tt.want) {
+				t.Errorf("validatePerson errors on %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateImportedPerson(t *testing.T) {
+	p := models.Person{ID: "I1", Name: "Ram Sharma", Gender: "Male"}
+	if errs := validateImportedPerson(p); len(errs) > 0 {
+		t.Errorf("validateImportedPerson without birth date = %v; want no errors", errs)
+	}
+	p.BirthDate, p.Gender = "ABT 1920", ""
+	if got := fields(validateImportedPerson(p)); !slices.Equal(got, []string{"gender", "birth_date"}) {
+		t.Errorf("validateImportedPerson errors on %v; want gender and birth_date", got)
+	}
+}
+
+func TestValidateLink(t *testing.T) {
+	date := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		link models.Link
+		want []string
+	}{
+		{"parent", models.Link{Relationship: "PARENT_CHILD", Source: "a", Target: "b"}, nil},
+		{"ended marriage", models.Link{Relationship: "SPOUSE", Source: "a", Target: "b",
+			StartDate: date("1970-01-01"), EndDate: date("1980-01-01"), EndReason: date("divorce")}, nil},
+		{"unknown type", models.Link{Relationship: "COUSIN", Source: "a", Target: "b"}, []string{"relationship"}},
+		{"missing persons", models.Link{Relationship: "SIBLING"}, []string{"source", "target"}},
+		{"self", models.Link{Relationship: "SIBLING", Source: "a", Target: "a"}, []string{"target"}},
+		{"dates not ISO", models.Link{Relationship: "SPOUSE", Source: "a", Target: "b", StartDate: date("1970"), EndDate: date("soon")},
+			[]string{"start_date", "end_date"}},
+		{"end before start", models.Link{Relationship: "SPOUSE", Source: "a", Target: "b", StartDate: date("1970-01-01"), EndDate: date("1969-01-01")},
+			[]string{"end_date"}},
+		{"end reason on parent", models.Link{Relationship: "PARENT_CHILD", Source: "a", Target: "b", EndReason: date("death")},
+			[]string{"end_reason"}},
+		{"unknown end reason", models.Link{Relationship: "SPOUSE", Source: "a", Target: "b", EndReason: date("separation")},
+			[]string{"end_reason"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(validateLink(tt.link)); !slices.Equal(got, tt.want) {
+				t.Errorf("validateLink errors on %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPersonRowIssues(t *testing.T) {
+	csv := `id,name,gender,is_alive,birth_date,death_date
+p-001,Pat Doe,Female,TRUE,1950-01-01,
+p-002,Sam Doe,Male,maybe,1952-01-01,
+p-001,Pat Again,Female,FALSE,1950-01-01,2000-01-01
+p-003,Lee Doe,Male,TRUE,1960-01-01,2010-01-01
+`
+	rows, err := csvdata.ReadPersons(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadPersons: %v", err)
+	}
+
+	type issue struct {
+		row    int
+		column string
+	}
+	var got []issue
+	for _, i := range personRowIssues(rows) {
+		if i.Severity != models.SeverityError {
+			t.Errorf("row %d %s: severity %s; want error", i.Row, i.Column, i.Severity)
+		}
+		got = append(got, issue{i.Row, i.Column})
+	}
+	want := []issue{{2, "is_alive"}, {3, "id"}, {4, "death_date"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("personRowIssues = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// AdminAuth returns a Gin middleware that requires the admin bearer token
+func AdminAuth(adminToken string) gin.HandlerFunc {
+	expectedToken := "Bearer " + adminToken
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != expectedToken {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "UNAUTHORIZED",
+					Message: "Invalid or missing admin token",
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -45,6 +45,9 @@ func CORSConfigProduction(allowedOrigins []string) gin.HandlerFunc {
 		AllowMethods: []string{
 			"GET",
 			"POST",
+			"PUT",
+			"PATCH",
+			"DELETE",
 			"OPTIONS",
 		},
 		AllowHeaders: []string{
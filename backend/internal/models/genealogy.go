@@ -0,0 +1,160 @@
+package models
+
+// HopKind is the relationship followed by one hop of a path between persons
+type HopKind string
+
+// Hop kinds, named after what the next person is to the previous one
+const (
+	HopParent  HopKind = "parent"
+	HopChild   HopKind = "child"
+	HopSpouse  HopKind = "spouse"
+	HopSibling HopKind = "sibling"
+)
+
+// Hop is one step of a path: the kind of relationship and the person reached
+type Hop struct {
+	Kind HopKind `json:"kind"`
+	To   string  `json:"to"`
+}
+
+// RelationshipResponse is the response for /api/relationship. Label names
+// what To is to From ("To is From's <label>") in Language; Romanized is its
+// Latin transliteration for languages written in another script. Side
+// ("paternal"/"maternal") and Seniority ("elder"/"younger") carry the
+// distinctions that some languages encode in the term itself.
+type RelationshipResponse struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Related   bool     `json:"related"`
+	Language  string   `json:"language"`
+	Label     string   `json:"label"`
+	Romanized string   `json:"romanized,omitempty"`
+	Side      string   `json:"side,omitempty"`
+	Seniority string   `json:"seniority,omitempty"`
+	Path      []string `json:"path"`
+	Hops      []Hop    `json:"hops"`
+}
+
+// PedigreeNode is one person in a pedigree. Ahnentafel numbers the subject 1;
+// the father of n is 2n and the mother 2n+1. When pedigree collapse makes an
+// ancestor appear again, the repeat records the number of the first
+// appearance in RepeatOf and its own ancestors are not listed twice.
+type PedigreeNode struct {
+	Ahnentafel int64         `json:"ahnentafel"`
+	Generation int           `json:"generation"`
+	Person     Person        `json:"person"`
+	RepeatOf   int64         `json:"repeat_of,omitempty"`
+	Father     *PedigreeNode `json:"father,omitempty"`
+	Mother     *PedigreeNode `json:"mother,omitempty"`
+}
+
+// AncestorsResponse is the response for /api/person/:id/ancestors
+type AncestorsResponse struct {
+	Subject     string `json:"subject"`
+	Generations int    `json:"generations"`    // generations requested
+	Depth       int    `json:"depth"`          // deepest generation found
+	Ancestors   int    `json:"ancestor_count"` // distinct ancestors
+	// PedigreeCollapse is set when an ancestor appears more than once
+	// within the requested generations
+	PedigreeCollapse bool `json:"pedigree_collapse"`
+	// Inbreeding is the subject's inbreeding coefficient over all known
+	// generations
+	Inbreeding float64       `json:"inbreeding_coefficient"`
+	Pedigree   *PedigreeNode `json:"pedigree"`
+	// Missing lists the Ahnentafel numbers of unknown parents within the
+	// requested generations
+	Missing []int64 `json:"missing"`
+}
+
+// DescendantNode is one descendant with their spouses and children. Number
+// is the d'Aboville or Henry number. A descendant reached a second time
+// through another line records the first Number in RepeatOf and their
+// children are not listed twice.
+type DescendantNode struct {
+	Number     string            `json:"number"`
+	Generation int               `json:"generation"`
+	Person     Person            `json:"person"`
+	RepeatOf   string            `json:"repeat_of,omitempty"`
+	Spouses    []Person          `json:"spouses"`
+	Children   []*DescendantNode `json:"children"`
+}
+
+// DescendantsResponse is the response for /api/person/:id/descendants
+type DescendantsResponse struct {
+	Subject     string          `json:"subject"`
+	Generations int             `json:"generations"`      // generations requested
+	Numbering   string          `json:"numbering"`        // "daboville" or "henry"
+	Depth       int             `json:"depth"`            // deepest generation found
+	Descendants int             `json:"descendant_count"` // distinct descendants
+	Tree        *DescendantNode `json:"tree"`
+}
+
+// PathResponse is the response for /api/path. Persons runs from From to To
+// and Links[i] connects Persons[i] and Persons[i+1], in the direction the
+// relationship is stored (PARENT_CHILD from parent to child).
+type PathResponse struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	BloodOnly bool     `json:"blood_only"`
+	Found     bool     `json:"found"`
+	Length    int      `json:"length"` // number of links
+	Persons   []Person `json:"persons"`
+	Links     []Link   `json:"links"`
+}
+
+// CommonAncestor is an ancestor shared by every person of a
+// /api/common-ancestors request. Distances gives the number of generations
+// from each of those persons up to the ancestor.
+type CommonAncestor struct {
+	Person    Person         `json:"person"`
+	Distances map[string]int `json:"distances"`
+	MRCA      bool           `json:"mrca"`
+}
+
+// CommonAncestorsResponse is the response for /api/common-ancestors. MRCA
+// repeats the common ancestors with no descendant among them, usually a
+// couple.
+type CommonAncestorsResponse struct {
+	Persons   []string         `json:"persons"`
+	Ancestors []CommonAncestor `json:"common_ancestors"`
+	MRCA      []CommonAncestor `json:"mrca"`
+}
+
+// RelatednessResponse is the response for /api/relatedness
+type RelatednessResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Relationship is Wright's coefficient of relationship, the expected
+	// fraction of genes shared by descent
+	Relationship float64 `json:"relationship_coefficient"`
+	// Kinship is the probability that alleles drawn from each are
+	// identical by descent; it is the inbreeding coefficient of their child
+	Kinship float64 `json:"kinship_coefficient"`
+	// Inbreeding maps From and To to their inbreeding coefficients
+	Inbreeding map[string]float64 `json:"inbreeding"`
+}
+
+// ExtendedRelative is a member of one extended family group. Label names
+// what Person is to the subject in the requested language, and Via is the
+// relative they are connected through: the parent for a grandparent, the
+// aunt or uncle for a cousin, the spouse for a parent-in-law.
+type ExtendedRelative struct {
+	Person    Person `json:"person"`
+	Label     string `json:"label"`
+	Romanized string `json:"romanized,omitempty"`
+	Via       Person `json:"via"`
+}
+
+// ExtendedFamilyResponse is the response for /api/person/:id/extended-family
+type ExtendedFamilyResponse struct {
+	Person        Person             `json:"person"`
+	Language      string             `json:"language"`
+	Grandparents  []ExtendedRelative `json:"grandparents"`
+	Grandchildren []ExtendedRelative `json:"grandchildren"`
+	AuntsUncles   []ExtendedRelative `json:"aunts_uncles"`
+	FirstCousins  []ExtendedRelative `json:"first_cousins"`
+	NiecesNephews []ExtendedRelative `json:"nieces_nephews"`
+	ParentsInLaw  []ExtendedRelative `json:"parents_in_law"`
+	SiblingsInLaw []ExtendedRelative `json:"siblings_in_law"`
+	ChildrenInLaw []ExtendedRelative `json:"children_in_law"`
+}
@@ -9,6 +9,8 @@ type Person struct {
 	IsAlive         bool     `json:"is_alive"`
 	BirthDate       string   `json:"birth_date"`
 	DeathDate       *string  `json:"death_date"`
+	BirthPlace      string   `json:"birth_place"`
+	DeathPlace      string   `json:"death_place"`
 	CurrentLocation string   `json:"current_location"`
 	Profession      string   `json:"profession"`
 	PhotoURL        string   `json:"photo_url"`
@@ -21,8 +23,29 @@ type Link struct {
 	Relationship string  `json:"relationship"`
 	StartDate    *string `json:"start_date,omitempty"`
 	EndDate      *string `json:"end_date,omitempty"`
+	EndReason    *string `json:"end_reason,omitempty"` // SPOUSE only, one of EndReasons
 }
 
+// Relationship types stored between persons
+const (
+	RelationshipParentChild = "PARENT_CHILD" // directed parent -> child
+	RelationshipSpouse      = "SPOUSE"       // undirected
+	RelationshipSibling     = "SIBLING"      // undirected
+)
+
+// RelationshipTypes lists every supported relationship type
+var RelationshipTypes = []string{RelationshipParentChild, RelationshipSpouse, RelationshipSibling}
+
+// Reasons a SPOUSE relationship ended
+const (
+	EndReasonDivorce   = "divorce"
+	EndReasonDeath     = "death"
+	EndReasonAnnulment = "annulment"
+)
+
+// EndReasons lists every supported end reason
+var EndReasons = []string{EndReasonDivorce, EndReasonDeath, EndReasonAnnulment}
+
 // TreeResponse is the response format for the /api/tree endpoint
 type TreeResponse struct {
 	Nodes []Person `json:"nodes"`
@@ -52,11 +75,28 @@ type ErrorDetail struct {
 	Details interface{} `json:"details,omitempty"`
 }
 
-// ImmediateFamily represents the immediate family of a person
+// ImmediateFamily represents the immediate family of a person. Siblings
+// are the explicit SIBLING links; the other sibling and step lists are
+// derived from PARENT_CHILD and SPOUSE links.
 type ImmediateFamily struct {
-	Person   Person   `json:"person"`
-	Parents  []Person `json:"parents"`
-	Spouse   *Person  `json:"spouse,omitempty"`
-	Children []Person `json:"children"`
-	Siblings []Person `json:"siblings"`
+	Person       Person        `json:"person"`
+	Parents      []Person      `json:"parents"`
+	Partnerships []Partnership `json:"partnerships"` // in chronological order
+	Children     []Person      `json:"children"`
+	Siblings     []Person      `json:"siblings"`
+	FullSiblings []Person      `json:"full_siblings"` // share both parents
+	HalfSiblings []Person      `json:"half_siblings"` // share one of two parents
+	StepSiblings []Person      `json:"step_siblings"` // children of a step-parent
+	StepParents  []Person      `json:"step_parents"`  // spouses of a parent
+}
+
+// Partnership is a SPOUSE relationship seen from one partner. A partnership
+// without an end date or reason ends by death when either partner has
+// died. Current marks the latest partnership that has not ended.
+type Partnership struct {
+	Partner   Person  `json:"partner"`
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+	EndReason *string `json:"end_reason,omitempty"`
+	Current   bool    `json:"current"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Snapshot is a backup of every person and relationship in the repository
+type Snapshot struct {
+	SchemaVersion int               `json:"schema_version"`
+	ExportedAt    time.Time         `json:"exported_at"`
+	Checksums     SnapshotChecksums `json:"checksums"`
+	Persons       []Person          `json:"persons"`
+	Relationships []Link            `json:"relationships"`
+}
+
+// SnapshotChecksums are the SHA-256 digests of the persons and relationships
+// of a snapshot, written as "sha256:<hex>"
+type SnapshotChecksums struct {
+	Persons       string `json:"persons"`
+	Relationships string `json:"relationships"`
+}
@@ -0,0 +1,74 @@
+package models
+
+// UpsertStatus describes what a write did to the stored record
+type UpsertStatus string
+
+const (
+	// UpsertCreated means the record did not exist and was created
+	UpsertCreated UpsertStatus = "created"
+	// UpsertUpdated means the record existed and at least one field changed
+	UpsertUpdated UpsertStatus = "updated"
+	// UpsertUnchanged means the record existed with identical fields
+	UpsertUnchanged UpsertStatus = "unchanged"
+	// UpsertFailed means the row was rejected and nothing was written
+	UpsertFailed UpsertStatus = "failed"
+)
+
+// UpsertResult reports the outcome of importing a single CSV row. For
+// relationship rows ID is "TYPE:person1_id:person2_id".
+type UpsertResult struct {
+	Row    int          `json:"row"`
+	ID     string       `json:"id"`
+	Status UpsertStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// UploadResponse is the response for a successful CSV or GEDCOM upload.
+// Warnings and Unmapped are only reported for GEDCOM.
+type UploadResponse struct {
+	Message    string            `json:"message"`
+	RowsParsed int               `json:"rows_parsed"`
+	Created    int               `json:"created"`
+	Updated    int               `json:"updated"`
+	Unchanged  int               `json:"unchanged"`
+	Failed     int               `json:"failed"`
+	Results    []UpsertResult    `json:"results"`
+	Warnings   []ValidationIssue `json:"warnings,omitempty"`
+	Unmapped   []UnmappedTag     `json:"unmapped_tags,omitempty"`
+}
+
+// Validation issue severities
+const (
+	SeverityError   = "error"   // the row cannot be imported
+	SeverityWarning = "warning" // the row can be imported but deserves a look
+)
+
+// ValidationIssue describes a problem with a single CSV row or cell
+type ValidationIssue struct {
+	Row      int    `json:"row"`
+	Column   string `json:"column,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ValidationReport lists every problem found in an uploaded CSV or GEDCOM
+// file. For GEDCOM, Row is the line number and Column the tag path.
+type ValidationReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Valid      bool              `json:"valid"`
+	RowsParsed int               `json:"rows_parsed"`
+	Errors     int               `json:"errors"`
+	Warnings   int               `json:"warnings"`
+	Issues     []ValidationIssue `json:"issues"`
+	Unmapped   []UnmappedTag     `json:"unmapped_tags,omitempty"`
+}
+
+// UnmappedTag counts a GEDCOM tag that an import skipped because persons
+// and relationships have no field for it. Tag is the path from the record,
+// such as "INDI.BIRT.SOUR".
+type UnmappedTag struct {
+	Tag       string `json:"tag"`
+	Count     int    `json:"count"`
+	FirstLine int    `json:"first_line"`
+}
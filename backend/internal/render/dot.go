@@ -0,0 +1,87 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// DOT writes tree as a Graphviz graph. Every generation is a rank of its
+// own; partners share it with a point between them from which their
+// children descend. Nodes show an initials avatar, the name and the years.
+// SIBLING links are left out, as siblings already share their parents.
+func DOT(w io.Writer, tree *models.TreeResponse, center string) error {
+	a := arrange(tree, center)
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph family_tree {")
+	fmt.Fprintln(bw, `  graph [rankdir=TB, splines=ortho, nodesep=0.4, ranksep=0.8, bgcolor="white"];`)
+	fmt.Fprintln(bw, `  node [shape=box, style="rounded,filled", fillcolor="white", color="#d1d5db", fontname="Helvetica", margin="0.15,0.1"];`)
+	fmt.Fprintln(bw, `  edge [dir=none, color="#9ca3af", penwidth=1.5];`)
+
+	// origin is the node each person's children descend from: the point
+	// between a couple, or the person
+	origin := make(map[string]string, len(a.persons))
+	for gen, units := range a.generations {
+		fmt.Fprintf(bw, "\n  // Generation %d\n", gen+1)
+		ranked := make([]string, 0, len(units))
+		for _, u := range units {
+			for _, id := range u.ids {
+				p := a.persons[id]
+				fmt.Fprintf(bw, "  %s [label=<%s>%s];\n", quote(id), dotLabel(p), dotHighlight(id == a.center))
+				ranked = append(ranked, quote(id))
+				origin[id] = quote(id)
+			}
+			if len(u.ids) == 2 {
+				union := quote("couple:" + u.ids[0] + ":" + u.ids[1])
+				fmt.Fprintf(bw, "  %s [shape=point, width=0.08, color=\"#9ca3af\"];\n", union)
+				fmt.Fprintf(bw, "  %s -> %s -> %s [weight=10];\n", quote(u.ids[0]), union, quote(u.ids[1]))
+				ranked = append(ranked, union)
+				origin[u.ids[0]] = union
+				origin[u.ids[1]] = union
+			}
+		}
+		fmt.Fprintf(bw, "  { rank=same; %s; }\n", strings.Join(ranked, "; "))
+	}
+
+	fmt.Fprintln(bw)
+	drawn := make(map[[2]string]bool)
+	for _, child := range a.order {
+		for _, parent := range a.parents[child] {
+			edge := [2]string{origin[parent], quote(child)}
+			if !drawn[edge] {
+				drawn[edge] = true
+				fmt.Fprintf(bw, "  %s -> %s;\n", edge[0], edge[1])
+			}
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotLabel is the HTML-like label of a person's node
+func dotLabel(p models.Person) string {
+	return fmt.Sprintf(`<TABLE BORDER="0" CELLSPACING="4">`+
+		`<TR><TD FIXEDSIZE="TRUE" WIDTH="40" HEIGHT="40" BGCOLOR="%s" STYLE="rounded"><FONT POINT-SIZE="14"><B>%s</B></FONT></TD></TR>`+
+		`<TR><TD><B>%s</B></TD></TR>`+
+		`<TR><TD><FONT POINT-SIZE="10" COLOR="#6b7280">%s</FONT></TD></TR>`+
+		`</TABLE>`,
+		avatarColor(p.Gender), html.EscapeString(initials(p.Name)), html.EscapeString(p.Name), html.EscapeString(years(p)))
+}
+
+// dotHighlight marks the centre person's node
+func dotHighlight(center bool) string {
+	if center {
+		return `, color="#fbbf24", penwidth=2.5`
+	}
+	return ""
+}
+
+// quote makes s a DOT ID
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,297 @@
+// Package render draws a tree from GetTreeData as a Graphviz DOT graph or
+// an SVG image. Both place each generation on its own row and partners side
+// by side; the SVG layout is computed here, in the way the frontend canvas
+// lays out the tree, so no Graphviz installation is needed.
+package render
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// arrangement sorts the persons of a tree into generations of units, where
+// a unit is a single person or a couple drawn side by side
+type arrangement struct {
+	persons    map[string]models.Person
+	order      []string // person IDs in tree order
+	center     string
+	generation map[string]int
+	parents    map[string][]string
+	children   map[string][]string
+	spouses    map[string][]string
+	// generations holds the units of every generation, oldest first
+	generations [][]*unit
+}
+
+type unit struct {
+	ids []string // one person, or two partners
+	x   float64  // centre, set by the SVG layout
+}
+
+// arrange groups tree into generations around center. Generations are
+// counted from center, or from the first person of a part of the tree that
+// is not connected to center: parents are one generation up, children one
+// down and spouses on the same one.
+func arrange(tree *models.TreeResponse, center string) *arrangement {
+	a := &arrangement{
+		persons:    make(map[string]models.Person, len(tree.Nodes)),
+		center:     center,
+		generation: make(map[string]int, len(tree.Nodes)),
+		parents:    make(map[string][]string),
+		children:   make(map[string][]string),
+		spouses:    make(map[string][]string),
+	}
+	for _, p := range tree.Nodes {
+		if _, dup := a.persons[p.ID]; !dup {
+			a.persons[p.ID] = p
+			a.order = append(a.order, p.ID)
+		}
+	}
+	for _, link := range tree.Links {
+		_, sourceOK := a.persons[link.Source]
+		_, targetOK := a.persons[link.Target]
+		if !sourceOK || !targetOK || link.Source == link.Target {
+			continue
+		}
+		switch link.Relationship {
+		case models.RelationshipParentChild:
+			a.parents[link.Target] = appendUnique(a.parents[link.Target], link.Source)
+			a.children[link.Source] = appendUnique(a.children[link.Source], link.Target)
+		case models.RelationshipSpouse:
+			a.spouses[link.Source] = appendUnique(a.spouses[link.Source], link.Target)
+			a.spouses[link.Target] = appendUnique(a.spouses[link.Target], link.Source)
+		}
+	}
+
+	starts := a.order
+	if _, ok := a.persons[center]; ok {
+		starts = append([]string{center}, a.order...)
+	}
+	for _, start := range starts {
+		if _, seen := a.generation[start]; !seen {
+			a.number(start)
+		}
+	}
+
+	// Shift generations so that the oldest is 0
+	oldest := 0
+	for _, gen := range a.generation {
+		oldest = min(oldest, gen)
+	}
+	for id := range a.generation {
+		a.generation[id] -= oldest
+	}
+
+	placed := make(map[string]bool, len(a.order))
+	for _, id := range a.order {
+		if placed[id] {
+			continue
+		}
+		gen := a.generation[id]
+		for len(a.generations) <= gen {
+			a.generations = append(a.generations, nil)
+		}
+
+		u := &unit{ids: []string{id}}
+		placed[id] = true
+		for _, spouse := range a.spouses[id] {
+			if !placed[spouse] && a.generation[spouse] == gen {
+				u.ids = append(u.ids, spouse)
+				placed[spouse] = true
+				break
+			}
+		}
+		a.generations[gen] = append(a.generations[gen], u)
+	}
+	return a
+}
+
+// number assigns generations to the persons connected to start, breadth
+// first, with start on generation 0
+func (a *arrangement) number(start string) {
+	a.generation[start] = 0
+	queue := []string{start}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		gen := a.generation[id]
+
+		for _, step := range []struct {
+			ids   []string
+			delta int
+		}{{a.parents[id], -1}, {a.children[id], 1}, {a.spouses[id], 0}} {
+			for _, next := range step.ids {
+				if _, seen := a.generation[next]; !seen {
+					a.generation[next] = gen + step.delta
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+}
+
+// Sizes of the SVG layout, in pixels
+const (
+	cardWidth    = 160
+	cardHeight   = 150
+	coupleGap    = 24 // between the cards of a couple
+	unitGap      = 48 // between neighbouring units
+	rowGap       = 80 // between generations
+	margin       = 32
+	avatarRadius = 30
+)
+
+// width is the width of a unit's cards
+func (u *unit) width() float64 {
+	return float64(len(u.ids)*cardWidth + (len(u.ids)-1)*coupleGap)
+}
+
+// cardX returns the left edge of the i-th card of a unit
+func (u *unit) cardX(i int) float64 {
+	return u.x - u.width()/2 + float64(i*(cardWidth+coupleGap))
+}
+
+// position sets the x of every unit. Like the frontend, units are first
+// placed under their parents from the oldest generation down, and then
+// parents are centred over their children from the youngest up; units that
+// would overlap are pushed to the right.
+func (a *arrangement) position() {
+	centers := make(map[string]float64, len(a.persons))
+	record := func(units []*unit) {
+		for _, u := range units {
+			for i, id := range u.ids {
+				centers[id] = u.cardX(i) + cardWidth/2
+			}
+		}
+	}
+	average := func(u *unit, relatives map[string][]string) (float64, bool) {
+		var sum float64
+		var n int
+		for _, id := range u.ids {
+			for _, relative := range relatives[id] {
+				if x, ok := centers[relative]; ok {
+					sum += x
+					n++
+				}
+			}
+		}
+		if n == 0 {
+			return 0, false
+		}
+		return sum / float64(n), true
+	}
+
+	for _, units := range a.generations {
+		for _, u := range units {
+			u.x, _ = average(u, a.parents)
+		}
+		separate(units)
+		record(units)
+	}
+
+	for gen := len(a.generations) - 2; gen >= 0; gen-- {
+		units := a.generations[gen]
+		for _, u := range units {
+			if x, ok := average(u, a.children); ok {
+				u.x = x
+			}
+		}
+		separate(units)
+		record(units)
+	}
+
+	// Move the drawing to the right of the left margin
+	left := 0.0
+	first := true
+	for _, units := range a.generations {
+		for _, u := range units {
+			if edge := u.x - u.width()/2; first || edge < left {
+				left, first = edge, false
+			}
+		}
+	}
+	for _, units := range a.generations {
+		for _, u := range units {
+			u.x += margin - left
+		}
+	}
+}
+
+// separate orders units by x and pushes each one right of its neighbour
+func separate(units []*unit) {
+	slices.SortStableFunc(units, func(a, b *unit) int { return cmp.Compare(a.x, b.x) })
+	for i := 1; i < len(units); i++ {
+		prev, u := units[i-1], units[i]
+		if least := prev.x + prev.width()/2 + unitGap + u.width()/2; u.x < least {
+			u.x = least
+		}
+	}
+}
+
+// initials returns the first letters of the first and last words of name
+func initials(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return "?"
+	}
+	first := []rune(words[0])[:1]
+	if len(words) == 1 {
+		return strings.ToUpper(string(first))
+	}
+	last := []rune(words[len(words)-1])[:1]
+	return strings.ToUpper(string(first) + string(last))
+}
+
+// years shows a lifespan as the frontend does, such as "1920 - 2000" or
+// "1990 - present"
+func years(p models.Person) string {
+	year := func(date string) string {
+		if len(date) >= 4 && strings.IndexFunc(date[:4], func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			return date[:4]
+		}
+		return "?"
+	}
+	end := "present"
+	switch {
+	case p.DeathDate != nil:
+		end = year(*p.DeathDate)
+	case !p.IsAlive:
+		end = "?"
+	}
+	return year(p.BirthDate) + " - " + end
+}
+
+// avatarColor is the background of the initials avatar of a gender
+func avatarColor(gender string) string {
+	switch gender {
+	case "Male":
+		return "#bfdbfe"
+	case "Female":
+		return "#fbcfe8"
+	}
+	return "#e5e7eb"
+}
+
+// truncate shortens text to max runes with an ellipsis, as the frontend does
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// nameLength is where names are truncated on cards, as on the canvas
+const nameLength = 18
+
+// appendUnique appends s unless it is already present
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
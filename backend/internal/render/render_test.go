@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func fixtureTree(t *testing.T) *models.TreeResponse {
+	t.Helper()
+	fx := repotest.LoadFixtures(t)
+	return &models.TreeResponse{Nodes: fx.Persons, Links: fx.Links}
+}
+
+func TestArrange(t *testing.T) {
+	tree := fixtureTree(t)
+	a := arrange(tree, "me-001")
+	a.position()
+
+	for _, link := range tree.Links {
+		source, target := a.generation[link.Source], a.generation[link.Target]
+		switch {
+		case link.Relationship == models.RelationshipParentChild && target != source+1:
+			t.Errorf("%s is generation %d but their parent %s is %d", link.Target, target, link.Source, source)
+		case link.Relationship != models.RelationshipParentChild && target != source:
+			t.Errorf("%s %s and %s are generations %d and %d", link.Relationship, link.Source, link.Target, source, target)
+		}
+	}
+
+	var couples []string
+	for gen, units := range a.generations {
+		for i, u := range units {
+			if len(u.ids) == 2 {
+				couples = append(couples, u.ids[0]+"+"+u.ids[1])
+			}
+			if u.x-u.width()/2 < margin {
+				t.Errorf("generation %d unit %v starts left of the margin", gen, u.ids)
+			}
+			if i > 0 {
+				prev := units[i-1]
+				if gap := (u.x - u.width()/2) - (prev.x + prev.width()/2); gap < unitGap {
+					t.Errorf("generation %d units %v and %v are %.1f apart", gen, prev.ids, u.ids, gap)
+				}
+			}
+		}
+	}
+	for _, want := range []string{"ggp-001+ggm-001", "dad-001+mom-001", "me-001+spouse-001"} {
+		if !slices.Contains(couples, want) {
+			t.Errorf("couples = %v; want %s among them", couples, want)
+		}
+	}
+}
+
+func TestDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := DOT(&buf, fixtureTree(t), "me-001"); err != nil {
+		t.Fatalf("DOT: %v", err)
+	}
+	out := buf.String()
+
+	ranks := regexp.MustCompile(`\{ rank=same; (.*); \}`).FindAllStringSubmatch(out, -1)
+	if len(ranks) != 5 {
+		t.Fatalf("found %d ranks; want one per generation (5)", len(ranks))
+	}
+	for _, group := range []string{`"dad-001"; "mom-001"; "couple:dad-001:mom-001"`, `"me-001"; "spouse-001"; "couple:me-001:spouse-001"`} {
+		if !strings.Contains(out, group) {
+			t.Errorf("no rank with %s", group)
+		}
+	}
+	for _, line := range []string{
+		`"couple:dad-001:mom-001" -> "me-001";`,
+		`"me-001" [label=<`,
+		`color="#fbbf24"`,
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("output has no %s", line)
+		}
+	}
+	if strings.Contains(out, `"dad-001" -> "me-001"`) {
+		t.Error("children of a couple should descend from the couple")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	tree := fixtureTree(t)
+	tree.Nodes = append(tree.Nodes, models.Person{ID: "amp-001", Name: `Tom & "Jerry" <Smith>`, Gender: "Other", BirthDate: "2020-01-01", IsAlive: true})
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, tree, "me-001"); err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+
+	// The image must be well-formed XML with a card per person
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	cards, titles := 0, []string{}
+	inTitle := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			inTitle = el.Name.Local == "title"
+			if el.Name.Local == "rect" && slices.ContainsFunc(el.Attr, func(a xml.Attr) bool { return a.Name.Local == "rx" }) {
+				cards++
+			}
+		case xml.CharData:
+			if inTitle {
+				titles = append(titles, string(el))
+			}
+		case xml.EndElement:
+			inTitle = false
+		}
+	}
+	if cards != len(tree.Nodes) {
+		t.Errorf("drew %d cards; want %d", cards, len(tree.Nodes))
+	}
+	if !slices.Contains(titles, `Tom & "Jerry" <Smith>`) {
+		t.Errorf("titles = %q; want the escaped name back", titles)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	death := "2000-05-01"
+	tests := []struct {
+		person   models.Person
+		initials string
+		years    string
+	}{
+		{models.Person{Name: "William Smith Sr.", BirthDate: "1900-01-15", DeathDate: &death}, "WS", "1900 - 2000"},
+		{models.Person{Name: "ąna", BirthDate: "1990-01-01", IsAlive: true}, "Ą", "1990 - present"},
+		{models.Person{Name: " ", BirthDate: "", IsAlive: false}, "?", "? - ?"},
+	}
+	for _, tt := range tests {
+		if got := initials(tt.person.Name); got != tt.initials {
+			t.Errorf("initials(%q) = %q; want %q", tt.person.Name, got, tt.initials)
+		}
+		if got := years(tt.person); got != tt.years {
+			t.Errorf("years(%+v) = %q; want %q", tt.person, got, tt.years)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// SVG writes tree as a standalone SVG image: a card per person with an
+// initials avatar, the name and the years, generations in rows, partners
+// side by side joined by a line, and lines from parents down to their
+// children. The centre person's card is outlined.
+func SVG(w io.Writer, tree *models.TreeResponse, center string) error {
+	a := arrange(tree, center)
+	a.position()
+
+	// cards maps a person to the top left corner of their card
+	type point struct{ x, y float64 }
+	cards := make(map[string]point, len(a.persons))
+	couples := make(map[string]*unit)
+	width, height := float64(2*margin), float64(2*margin)
+	for gen, units := range a.generations {
+		y := float64(margin + gen*(cardHeight+rowGap))
+		for _, u := range units {
+			for i, id := range u.ids {
+				cards[id] = point{u.cardX(i), y}
+				if len(u.ids) == 2 {
+					couples[id] = u
+				}
+			}
+			width = max(width, u.x+u.width()/2+margin)
+		}
+		height = y + cardHeight + margin
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintln(bw, `<rect width="100%" height="100%" fill="#ffffff"/>`)
+
+	// Lines go under the cards
+	fmt.Fprintln(bw, `<g fill="none" stroke="#9ca3af" stroke-width="2">`)
+	for _, units := range a.generations {
+		for _, u := range units {
+			if len(u.ids) == 2 {
+				left, right := cards[u.ids[0]], cards[u.ids[1]]
+				y := left.y + cardHeight/2
+				fmt.Fprintf(bw, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`+"\n", left.x+cardWidth, y, right.x, y)
+			}
+		}
+	}
+	drawn := make(map[[3]float64]bool)
+	for _, child := range a.order {
+		to := cards[child]
+		toX := to.x + cardWidth/2
+		for _, parent := range a.parents[child] {
+			// Children of a couple descend from the line between them
+			from := cards[parent]
+			fromX, fromY := from.x+cardWidth/2, from.y+cardHeight
+			if u := couples[parent]; u != nil && slices.Contains(a.parents[child], other(u, parent)) {
+				fromX, fromY = u.x, from.y+cardHeight/2
+			}
+			key := [3]float64{fromX, fromY, toX}
+			if drawn[key] {
+				continue
+			}
+			drawn[key] = true
+			midY := to.y - rowGap/2
+			fmt.Fprintf(bw, `<path d="M%.1f %.1f V%.1f H%.1f V%.1f"/>`+"\n", fromX, fromY, midY, toX, to.y)
+		}
+	}
+	fmt.Fprintln(bw, `</g>`)
+
+	for _, id := range a.order {
+		p, at := a.persons[id], cards[id]
+		stroke, strokeWidth := "#e5e7eb", 2
+		if id == a.center {
+			stroke, strokeWidth = "#fbbf24", 3
+		}
+		cx := at.x + cardWidth/2
+		fmt.Fprintf(bw, `<g><title>%s</title>`+"\n", html.EscapeString(p.Name))
+		fmt.Fprintf(bw, `<rect x="%.1f" y="%.1f" width="%d" height="%d" rx="16" fill="#ffffff" stroke="%s" stroke-width="%d"/>`+"\n",
+			at.x, at.y, cardWidth, cardHeight, stroke, strokeWidth)
+		fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%d" fill="%s"/>`+"\n", cx, at.y+16+avatarRadius, avatarRadius, avatarColor(p.Gender))
+		fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central" font-size="20" font-weight="bold" fill="#374151">%s</text>`+"\n",
+			cx, at.y+16+avatarRadius, html.EscapeString(initials(p.Name)))
+		fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="14" font-weight="500" fill="#111827">%s</text>`+"\n",
+			cx, at.y+104, html.EscapeString(truncate(p.Name, nameLength)))
+		fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="12" fill="#6b7280">%s</text>`+"\n",
+			cx, at.y+128, html.EscapeString(years(p)))
+		fmt.Fprintln(bw, `</g>`)
+	}
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+// other returns the partner of id in a couple
+func other(u *unit, id string) string {
+	if u.ids[0] == id {
+		return u.ids[1]
+	}
+	return u.ids[0]
+}
@@ -0,0 +1,135 @@
+// Package snapshot writes and verifies JSON backups of every person and
+// relationship, independent of the storage backend they came from.
+package snapshot
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+// SchemaVersion is the snapshot format New writes. Read accepts versions up
+// to this one.
+const SchemaVersion = 1
+
+// VersionError is returned for a snapshot with an unsupported schema version
+type VersionError struct {
+	Version int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("unsupported schema version %d (supported: 1 to %d)", e.Version, SchemaVersion)
+}
+
+// ChecksumError is returned when a section of a snapshot does not match its
+// checksum
+type ChecksumError struct {
+	Section  string // "persons" or "relationships"
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Section, e.Expected, e.Actual)
+}
+
+// New creates a snapshot of persons and links. Both are sorted so that the
+// same data always gives the same document apart from ExportedAt.
+func New(persons []models.Person, links []models.Link, exportedAt time.Time) models.Snapshot {
+	persons, links = sorted(persons, links)
+	return models.Snapshot{
+		SchemaVersion: SchemaVersion,
+		ExportedAt:    exportedAt.UTC(),
+		Checksums:     Checksums(persons, links),
+		Persons:       persons,
+		Relationships: links,
+	}
+}
+
+// document is a snapshot with its sections as written. The checksums cover
+// those bytes rather than their re-encoding, so that fields added to or
+// dropped from the models do not invalidate older snapshots.
+type document struct {
+	SchemaVersion int                      `json:"schema_version"`
+	ExportedAt    time.Time                `json:"exported_at"`
+	Checksums     models.SnapshotChecksums `json:"checksums"`
+	Persons       json.RawMessage          `json:"persons"`
+	Relationships json.RawMessage          `json:"relationships"`
+}
+
+// Read decodes a snapshot and verifies its schema version and checksums. In
+// schema version 1 a checksum is the SHA-256 of its section's JSON without
+// insignificant whitespace, so indenting a snapshot does not invalidate it.
+func Read(r io.Reader) (*models.Snapshot, error) {
+	var doc document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid snapshot JSON: %w", err)
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > SchemaVersion {
+		return nil, &VersionError{Version: doc.SchemaVersion}
+	}
+
+	if actual := digest(doc.Persons); actual != doc.Checksums.Persons {
+		return nil, &ChecksumError{Section: "persons", Expected: doc.Checksums.Persons, Actual: actual}
+	}
+	if actual := digest(doc.Relationships); actual != doc.Checksums.Relationships {
+		return nil, &ChecksumError{Section: "relationships", Expected: doc.Checksums.Relationships, Actual: actual}
+	}
+
+	s := models.Snapshot{SchemaVersion: doc.SchemaVersion, ExportedAt: doc.ExportedAt, Checksums: doc.Checksums}
+	if err := json.Unmarshal(doc.Persons, &s.Persons); err != nil {
+		return nil, fmt.Errorf("invalid snapshot persons: %w", err)
+	}
+	if err := json.Unmarshal(doc.Relationships, &s.Relationships); err != nil {
+		return nil, fmt.Errorf("invalid snapshot relationships: %w", err)
+	}
+	return &s, nil
+}
+
+// Checksums computes the checksums of persons and links. They do not
+// depend on the order of either.
+func Checksums(persons []models.Person, links []models.Link) models.SnapshotChecksums {
+	persons, links = sorted(persons, links)
+	return models.SnapshotChecksums{
+		Persons:       checksum(persons),
+		Relationships: checksum(links),
+	}
+}
+
+// sorted returns copies of persons ordered by ID and links by type and
+// endpoints. Nil slices become empty ones, which encode as [] rather than
+// null.
+func sorted(persons []models.Person, links []models.Link) ([]models.Person, []models.Link) {
+	persons = append([]models.Person{}, persons...)
+	slices.SortStableFunc(persons, func(a, b models.Person) int { return cmp.Compare(a.ID, b.ID) })
+
+	links = append([]models.Link{}, links...)
+	slices.SortStableFunc(links, func(a, b models.Link) int {
+		return cmp.Or(cmp.Compare(a.Relationship, b.Relationship), cmp.Compare(a.Source, b.Source), cmp.Compare(a.Target, b.Target))
+	})
+	return persons, links
+}
+
+// checksum hashes the JSON encoding of v
+func checksum(v any) string {
+	// Persons and links always encode
+	data, _ := json.Marshal(v)
+	return digest(data)
+}
+
+// digest hashes a section of a snapshot as schema version 1 defines
+func digest(section []byte) string {
+	var compact bytes.Buffer
+	// A section that is not valid JSON stays empty and fails to match
+	json.Compact(&compact, section)
+	sum := sha256.Sum256(compact.Bytes())
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heemankverma/family_tree/backend/internal/database/repotest"
+	"github.com/heemankverma/family_tree/backend/internal/models"
+)
+
+func TestRoundTrip(t *testing.T) {
+	fx := repotest.LoadFixtures(t)
+	exportedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("IST", 19800))
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(New(fx.Persons, fx.Links, exportedAt)); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if s.SchemaVersion != SchemaVersion || !s.ExportedAt.Equal(exportedAt) || s.ExportedAt.Location() != time.UTC {
+		t.Errorf("schema_version = %d, exported_at = %v", s.SchemaVersion, s.ExportedAt)
+	}
+	if len(s.Persons) != len(fx.Persons) || len(s.Relationships) != len(fx.Links) {
+		t.Fatalf("read %d persons and %d relationships; want %d and %d",
+			len(s.Persons), len(s.Relationships), len(fx.Persons), len(fx.Links))
+	}
+	if !slices.IsSortedFunc(s.Persons, func(a, b models.Person) int { return strings.Compare(a.ID, b.ID) }) {
+		t.Error("persons are not sorted by ID")
+	}
+
+	// Checksums do not depend on order
+	reversed := slices.Clone(fx.Persons)
+	slices.Reverse(reversed)
+	if got := Checksums(reversed, fx.Links); got != s.Checksums {
+		t.Errorf("checksums of reordered data = %+v; want %+v", got, s.Checksums)
+	}
+}
+
+func TestReadOtherModels(t *testing.T) {
+	// A snapshot written by a version with a person field this one lacks,
+	// indented by hand
+	persons := `[{"id":"p-001","name":"Pat Doe","gender":"Female","nickname":"Patty"}]`
+	sum := sha256.Sum256([]byte(persons))
+	empty := sha256.Sum256([]byte("[]"))
+	input := fmt.Sprintf(`{
+  "schema_version": 1,
+  "exported_at": "2024-05-01T00:00:00Z",
+  "checksums": {"persons": "sha256:%x", "relationships": "sha256:%x"},
+  "persons": [
+    {"id": "p-001", "name": "Pat Doe", "gender": "Female", "nickname": "Patty"}
+  ],
+  "relationships": []
+}`, sum, empty)
+
+	s, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(s.Persons) != 1 || s.Persons[0].ID != "p-001" || len(s.Relationships) != 0 {
+		t.Errorf("read persons %+v and relationships %+v", s.Persons, s.Relationships)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	fx := repotest.LoadFixtures(t)
+	encode := func(edit func(s *models.Snapshot)) string {
+		s := New(fx.Persons, fx.Links, time.Now())
+		edit(&s)
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	var versionErr *VersionError
+	var checksumErr *ChecksumError
+	tests := []struct {
+		name    string
+		input   string
+		check   func(error) bool
+		section string
+	}{
+		{"not JSON", "persons,relationships", func(err error) bool { return err != nil }, ""},
+		{"missing version", encode(func(s *models.Snapshot) { s.SchemaVersion = 0 }), func(err error) bool { return errors.As(err, &versionErr) }, ""},
+		{"future version", encode(func(s *models.Snapshot) { s.SchemaVersion = SchemaVersion + 1 }), func(err error) bool { return errors.As(err, &versionErr) }, ""},
+		{"edited person", encode(func(s *models.Snapshot) { s.Persons[0].Name = "Someone Else" }), func(err error) bool { return errors.As(err, &checksumErr) }, "persons"},
+		{"dropped relationship", encode(func(s *models.Snapshot) { s.Relationships = s.Relationships[1:] }), func(err error) bool { return errors.As(err, &checksumErr) }, "relationships"},
+	}
+
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.input))
+		if !tt.check(err) {
+			t.Errorf("%s: error = %v", tt.name, err)
+			continue
+		}
+		if tt.section != "" && checksumErr.Section != tt.section {
+			t.Errorf("%s: section = %s; want %s", tt.name, checksumErr.Section, tt.section)
+		}
+	}
+}
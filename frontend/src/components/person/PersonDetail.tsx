@@ -94,8 +94,12 @@ export function PersonDetail({ person, onClose, onPersonClick, compact = false }
             </div>
           ) : family ? (
             <div className="space-y-3">
-              {family.spouse && (
-                <CompactFamilyGroup label="Spouse" members={[family.spouse]} onMemberClick={onPersonClick} />
+              {family.partnerships.length > 0 && (
+                <CompactFamilyGroup
+                  label={family.partnerships.length > 1 ? 'Spouses' : 'Spouse'}
+                  members={family.partnerships.map((p) => p.partner)}
+                  onMemberClick={onPersonClick}
+                />
               )}
               {family.children.length > 0 && (
                 <CompactFamilyGroup label="Children" members={family.children} onMemberClick={onPersonClick} />
@@ -162,14 +166,14 @@ export function PersonDetail({ person, onClose, onPersonClick, compact = false }
         <DetailRow
           icon={<Calendar className="w-4 h-4" />}
           label="Born"
-          value={formatDate(person.birth_date)}
+          value={[formatDate(person.birth_date), person.birth_place].filter(Boolean).join(', ')}
         />
 
         {person.death_date && (
           <DetailRow
             icon={<Calendar className="w-4 h-4" />}
             label="Died"
-            value={formatDate(person.death_date)}
+            value={[formatDate(person.death_date), person.death_place].filter(Boolean).join(', ')}
           />
         )}
 
@@ -216,11 +220,11 @@ export function PersonDetail({ person, onClose, onPersonClick, compact = false }
           </div>
         ) : family ? (
           <div className="space-y-3">
-            {/* Spouse */}
-            {family.spouse && (
+            {/* Spouses, in chronological order */}
+            {family.partnerships.length > 0 && (
               <FamilyGroup
-                label="Spouse"
-                members={[family.spouse]}
+                label={family.partnerships.length > 1 ? 'Spouses' : 'Spouse'}
+                members={family.partnerships.map((p) => p.partner)}
                 onMemberClick={onPersonClick}
               />
             )}
@@ -13,6 +13,8 @@ export interface Person {
   is_alive: boolean;
   birth_date: string;
   death_date: string | null;
+  birth_place: string;
+  death_place: string;
   current_location: string;
   profession: string;
   photo_url: string;
@@ -25,6 +27,7 @@ export interface Link {
   relationship: RelationshipType;
   start_date?: string;
   end_date?: string;
+  end_reason?: 'divorce' | 'death' | 'annulment';
 }
 
 // CoupleSelection for sidebar display
@@ -43,13 +46,26 @@ export interface TreeResponse {
   links: Link[];
 }
 
+// Partnership is a SPOUSE relationship seen from one partner
+export interface Partnership {
+  partner: Person;
+  start_date?: string;
+  end_date?: string;
+  end_reason?: 'divorce' | 'death' | 'annulment';
+  current: boolean;
+}
+
 // ImmediateFamily from the API
 export interface ImmediateFamily {
   person: Person;
   parents: Person[];
-  spouse: Person | null;
+  partnerships: Partnership[]; // chronological
   children: Person[];
-  siblings: Person[];
+  siblings: Person[]; // explicit SIBLING links
+  full_siblings: Person[];
+  half_siblings: Person[];
+  step_siblings: Person[];
+  step_parents: Person[];
 }
 
 // QueryResponse from the API
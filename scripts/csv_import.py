@@ -63,7 +63,9 @@ def import_persons(driver, csv_path):
                 'death_date': row.get('death_date', '').strip() or None,
                 'current_location': row.get('current_location', '').strip(),
                 'profession': row.get('profession', '').strip(),
-                'photo_url': row.get('photo_url', '').strip() or ''
+                'photo_url': row.get('photo_url', '').strip() or '',
+                'birth_place': row.get('birth_place', '').strip(),
+                'death_place': row.get('death_place', '').strip()
             }
             persons.append(person)
 
@@ -80,7 +82,9 @@ def import_persons(driver, csv_path):
             p.death_date = person.death_date,
             p.current_location = person.current_location,
             p.profession = person.profession,
-            p.photo_url = person.photo_url
+            p.photo_url = person.photo_url,
+            p.birth_place = person.birth_place,
+            p.death_place = person.death_place
         """
         session.run(query, persons=persons)
 